@@ -0,0 +1,261 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+	"github.com/Farber98/cc-solutions/compress/container"
+)
+
+// Magic starts and ends every archive.
+const Magic = "HZAR"
+
+// trailerSize is the size of the directory offset followed by the closing magic.
+const trailerSize = 8 + len(Magic)
+
+// ErrNotFound is returned when a requested entry is not in the archive.
+var ErrNotFound = errors.New("entry not found")
+
+// Entry describes a file or directory stored in an archive.
+type Entry struct {
+	// Name is the slash separated path of the entry inside the archive.
+	Name    string
+	Mode    fs.FileMode
+	ModTime time.Time
+	// Size is the size of the original file.
+	Size uint64
+	// Offset is the position of the compressed data from the start of the archive.
+	Offset uint64
+	// CompressedSize is the size of the compressed data.
+	CompressedSize uint64
+	// Checksum is the CRC32 (IEEE) of the original file.
+	Checksum uint32
+}
+
+// Writer writes an archive: the magic, the independently Huffman coded entries, and a central
+// directory listing all entries followed by a trailer pointing at it.
+type Writer struct {
+	w       io.Writer
+	offset  uint64
+	entries []Entry
+	err     error
+}
+
+// NewWriter creates an archive writer on top of w and writes the magic. Close must be called to write the directory.
+func NewWriter(w io.Writer) *Writer {
+	aw := &Writer{w: w}
+	aw.write([]byte(Magic))
+	return aw
+}
+
+// write writes p and advances the offset, remembering the first error.
+func (aw *Writer) write(p []byte) {
+	if aw.err != nil {
+		return
+	}
+	n, err := aw.w.Write(p)
+	aw.offset += uint64(n)
+	aw.err = err
+}
+
+// AddFile compresses contents and adds them as a regular file entry.
+func (aw *Writer) AddFile(name string, mode fs.FileMode, modTime time.Time, contents []byte) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+
+	compressor := &compress.HuffmanCompressor{}
+	encoded := compressor.Encode(contents, nil)
+	entry := Entry{
+		Name:           name,
+		Mode:           mode,
+		ModTime:        modTime,
+		Size:           uint64(len(contents)),
+		Offset:         aw.offset,
+		CompressedSize: uint64(len(encoded)),
+		Checksum:       container.Checksum(contents),
+	}
+	aw.write(encoded)
+	aw.entries = append(aw.entries, entry)
+	return aw.err
+}
+
+// AddDir adds a directory entry, which has no data.
+func (aw *Writer) AddDir(name string, mode fs.FileMode, modTime time.Time) error {
+	if err := validName(name); err != nil {
+		return err
+	}
+	aw.entries = append(aw.entries, Entry{Name: name, Mode: mode | fs.ModeDir, ModTime: modTime, Offset: aw.offset})
+	return aw.err
+}
+
+// Entries returns the entries added so far.
+func (aw *Writer) Entries() []Entry {
+	return aw.entries
+}
+
+// Close writes the central directory and the trailer. It does not close the underlying writer.
+func (aw *Writer) Close() error {
+	directoryOffset := aw.offset
+
+	buf := binary.AppendUvarint(nil, uint64(len(aw.entries)))
+	for _, e := range aw.entries {
+		buf = binary.AppendUvarint(buf, uint64(len(e.Name)))
+		buf = append(buf, e.Name...)
+		buf = binary.AppendUvarint(buf, uint64(e.Mode))
+		buf = binary.AppendVarint(buf, e.ModTime.UnixNano())
+		buf = binary.AppendUvarint(buf, e.Size)
+		buf = binary.AppendUvarint(buf, e.Offset)
+		buf = binary.AppendUvarint(buf, e.CompressedSize)
+		buf = binary.BigEndian.AppendUint32(buf, e.Checksum)
+	}
+	buf = binary.BigEndian.AppendUint64(buf, directoryOffset)
+	buf = append(buf, Magic...)
+
+	aw.write(buf)
+	return aw.err
+}
+
+// Reader gives access to the entries of an archive through its central directory.
+type Reader struct {
+	r       io.ReaderAt
+	entries []Entry
+}
+
+// NewReader reads the central directory of an archive of the given size.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	if size < int64(len(Magic)+trailerSize) {
+		return nil, fmt.Errorf("invalid archive: too small")
+	}
+
+	magic := make([]byte, len(Magic))
+	if _, err := r.ReadAt(magic, 0); err != nil {
+		return nil, fmt.Errorf("error reading archive magic: %w", err)
+	}
+	trailer := make([]byte, trailerSize)
+	if _, err := r.ReadAt(trailer, size-int64(trailerSize)); err != nil {
+		return nil, fmt.Errorf("error reading archive trailer: %w", err)
+	}
+	if string(magic) != Magic || string(trailer[8:]) != Magic {
+		return nil, fmt.Errorf("invalid archive magic")
+	}
+
+	directoryOffset := binary.BigEndian.Uint64(trailer)
+	directoryEnd := uint64(size) - uint64(trailerSize)
+	if directoryOffset < uint64(len(Magic)) || directoryOffset > directoryEnd {
+		return nil, fmt.Errorf("invalid archive directory offset: %d", directoryOffset)
+	}
+	directory := make([]byte, directoryEnd-directoryOffset)
+	if _, err := r.ReadAt(directory, int64(directoryOffset)); err != nil {
+		return nil, fmt.Errorf("error reading archive directory: %w", err)
+	}
+
+	entries, err := parseDirectory(directory, directoryOffset)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{r: r, entries: entries}, nil
+}
+
+// parseDirectory decodes the central directory, checking that every entry lies before it.
+func parseDirectory(directory []byte, directoryOffset uint64) ([]Entry, error) {
+	reader := bytes.NewReader(directory)
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive directory: %w", err)
+	}
+	if count > uint64(len(directory)) {
+		return nil, fmt.Errorf("invalid archive entry count: %d", count)
+	}
+
+	entries := make([]Entry, 0, count)
+	for i := uint64(0); i < count; i++ {
+		var e Entry
+		nameLength, err := binary.ReadUvarint(reader)
+		if err != nil || nameLength > uint64(reader.Len()) {
+			return nil, fmt.Errorf("invalid archive entry %d", i)
+		}
+		name := make([]byte, nameLength)
+		reader.Read(name)
+		e.Name = string(name)
+
+		mode, err1 := binary.ReadUvarint(reader)
+		modTime, err2 := binary.ReadVarint(reader)
+		size, err3 := binary.ReadUvarint(reader)
+		offset, err4 := binary.ReadUvarint(reader)
+		compressedSize, err5 := binary.ReadUvarint(reader)
+		checksum := make([]byte, 4)
+		_, err6 := io.ReadFull(reader, checksum)
+		if err := errors.Join(err1, err2, err3, err4, err5, err6); err != nil {
+			return nil, fmt.Errorf("invalid archive entry %s: %w", e.Name, err)
+		}
+
+		e.Mode = fs.FileMode(mode)
+		e.ModTime = time.Unix(0, modTime)
+		e.Size = size
+		e.Offset = offset
+		e.CompressedSize = compressedSize
+		e.Checksum = binary.BigEndian.Uint32(checksum)
+
+		if validName(e.Name) != nil || offset > directoryOffset || compressedSize > directoryOffset-offset {
+			return nil, fmt.Errorf("invalid archive entry %s", e.Name)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// Entries returns the entries of the archive in the order they were added.
+func (ar *Reader) Entries() []Entry {
+	return ar.entries
+}
+
+// Find returns the entry with the given name.
+func (ar *Reader) Find(name string) (Entry, error) {
+	for _, e := range ar.entries {
+		if e.Name == name {
+			return e, nil
+		}
+	}
+	return Entry{}, fmt.Errorf("%w: %s", ErrNotFound, name)
+}
+
+// ReadEntry decodes the data of a file entry and verifies its size and checksum.
+func (ar *Reader) ReadEntry(e Entry) ([]byte, error) {
+	if e.Mode.IsDir() {
+		return nil, nil
+	}
+
+	encoded := make([]byte, e.CompressedSize)
+	if _, err := ar.r.ReadAt(encoded, int64(e.Offset)); err != nil {
+		return nil, fmt.Errorf("error reading entry %s: %w", e.Name, err)
+	}
+
+	decompressor := &compress.HuffmanDecompressor{}
+	contents, err := decompressor.Decode(encoded, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding entry %s: %w", e.Name, err)
+	}
+
+	header := container.Header{Length: e.Size, Checksum: e.Checksum}
+	if err := header.Verify(contents); err != nil {
+		return nil, fmt.Errorf("entry %s: %w", e.Name, err)
+	}
+	return contents, nil
+}
+
+// validName checks that name is a clean relative slash separated path, so extraction cannot escape the destination.
+func validName(name string) error {
+	if name == "" || name == "." || !fs.ValidPath(name) || strings.Contains(name, "\\") || path.Clean(name) != name {
+		return fmt.Errorf("invalid entry name: %q", name)
+	}
+	return nil
+}
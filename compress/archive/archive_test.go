@@ -0,0 +1,215 @@
+package archive
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriterReaderRoundTrip(t *testing.T) {
+	modTime := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	files := map[string][]byte{
+		"root/a.txt":     []byte("aabbccddddccbbaa\n"),
+		"root/sub/b.bin": {0, 1, 2, 255, 10, 44},
+		"root/empty":     {},
+	}
+
+	var buf bytes.Buffer
+	aw := NewWriter(&buf)
+	if err := aw.AddDir("root", 0755, modTime); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"root/a.txt", "root/empty", "root/sub/b.bin"} {
+		if err := aw.AddFile(name, 0640, modTime, files[name]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := aw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ar, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ar.Entries()) != 4 {
+		t.Fatalf("Expected 4 entries, got %d", len(ar.Entries()))
+	}
+
+	root := ar.Entries()[0]
+	if root.Name != "root" || !root.Mode.IsDir() || !root.ModTime.Equal(modTime) {
+		t.Errorf("Unexpected directory entry %+v", root)
+	}
+
+	for name, contents := range files {
+		e, err := ar.Find(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Mode != 0640 || e.Size != uint64(len(contents)) || !e.ModTime.Equal(modTime) {
+			t.Errorf("Unexpected entry %+v", e)
+		}
+		read, err := ar.ReadEntry(e)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !bytes.Equal(read, contents) {
+			t.Errorf("Expected %q, got %q", contents, read)
+		}
+	}
+
+	if _, err := ar.Find("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestReadEntryDetectsCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	aw := NewWriter(&buf)
+	aw.AddFile("a.txt", 0644, time.Now(), []byte(strings.Repeat("corruption ", 20)))
+	aw.Close()
+
+	contents := buf.Bytes()
+	contents[len(Magic)+20] ^= 0xff
+
+	ar, err := NewReader(bytes.NewReader(contents), int64(len(contents)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, _ := ar.Find("a.txt")
+	if _, err := ar.ReadEntry(e); err == nil {
+		t.Error("Expected an error for a corrupt entry, got nil")
+	}
+}
+
+func TestNewReaderErrors(t *testing.T) {
+	var buf bytes.Buffer
+	aw := NewWriter(&buf)
+	aw.AddFile("a.txt", 0644, time.Now(), []byte("a"))
+	aw.Close()
+	valid := buf.Bytes()
+
+	testCases := []struct {
+		name     string
+		contents []byte
+	}{
+		{name: "Too_small", contents: []byte(Magic)},
+		{name: "Bad_magic", contents: append([]byte("NOPE"), valid[4:]...)},
+		{name: "Truncated", contents: valid[:len(valid)-1]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewReader(bytes.NewReader(tc.contents), int64(len(tc.contents))); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestInvalidNames(t *testing.T) {
+	aw := NewWriter(&bytes.Buffer{})
+	for _, name := range []string{"", ".", "/etc/passwd", "../escape", "a/../../b", "a//b", "a\\b"} {
+		if err := aw.AddFile(name, 0644, time.Now(), nil); err == nil {
+			t.Errorf("Expected an error for name %q, got nil", name)
+		}
+	}
+}
+
+func TestAddTreeAndExtract(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "tree")
+	writeFile(t, filepath.Join(src, "a.txt"), "first file\n", 0600)
+	writeFile(t, filepath.Join(src, "nested", "b.txt"), "second file\n", 0644)
+	os.Mkdir(filepath.Join(src, "empty"), 0700)
+
+	modTime := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	os.Chtimes(filepath.Join(src, "a.txt"), modTime, modTime)
+
+	var buf bytes.Buffer
+	aw := NewWriter(&buf)
+	if err := AddTree(aw, src); err != nil {
+		t.Fatal(err)
+	}
+	aw.Close()
+
+	ar, err := NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Extract everything
+	dest := t.TempDir()
+	if _, err := Extract(ar, dest, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assertFile(t, filepath.Join(dest, "tree", "a.txt"), "first file\n", 0600)
+	assertFile(t, filepath.Join(dest, "tree", "nested", "b.txt"), "second file\n", 0644)
+	if info, err := os.Stat(filepath.Join(dest, "tree", "empty")); err != nil || info.Mode().Perm() != 0700 {
+		t.Errorf("Expected empty directory with mode 0700, got %v %v", info, err)
+	}
+	if info, _ := os.Stat(filepath.Join(dest, "tree", "a.txt")); !info.ModTime().Equal(modTime) {
+		t.Errorf("Expected modification time %v, got %v", modTime, info.ModTime())
+	}
+
+	// Extract a single directory
+	dest = t.TempDir()
+	extracted, err := Extract(ar, dest, false, "tree/nested")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(extracted) != 2 {
+		t.Errorf("Expected 2 extracted entries, got %d", len(extracted))
+	}
+	assertFile(t, filepath.Join(dest, "tree", "nested", "b.txt"), "second file\n", 0644)
+	if _, err := os.Stat(filepath.Join(dest, "tree", "a.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Expected a.txt not to be extracted")
+	}
+
+	if _, err := Extract(ar, dest, false, "tree/missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	// Existing files are only replaced when forced, and nothing is extracted otherwise
+	writeFile(t, filepath.Join(dest, "tree", "nested", "b.txt"), "changed\n", 0644)
+	if _, err := Extract(ar, dest, false); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Expected fs.ErrExist, got %v", err)
+	}
+	assertFile(t, filepath.Join(dest, "tree", "nested", "b.txt"), "changed\n", 0644)
+	if _, err := os.Stat(filepath.Join(dest, "tree", "a.txt")); !errors.Is(err, fs.ErrNotExist) {
+		t.Error("Expected a.txt not to be extracted")
+	}
+	if _, err := Extract(ar, dest, true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	assertFile(t, filepath.Join(dest, "tree", "nested", "b.txt"), "second file\n", 0644)
+}
+
+func writeFile(t *testing.T, filePath, contents string, mode fs.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filePath, []byte(contents), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertFile(t *testing.T, filePath, contents string, mode fs.FileMode) {
+	t.Helper()
+	read, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(read) != contents {
+		t.Errorf("Expected %q in %s, got %q", contents, filePath, read)
+	}
+	info, _ := os.Stat(filePath)
+	if info.Mode().Perm() != mode {
+		t.Errorf("Expected mode %v for %s, got %v", mode, filePath, info.Mode().Perm())
+	}
+}
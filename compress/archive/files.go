@@ -0,0 +1,147 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Farber98/cc-solutions/compress/internal/atomicfile"
+)
+
+// AddTree walks root and adds every directory and regular file under it. Entry names start with
+// the base name of root, so extracting recreates it. Other file types such as symlinks are rejected.
+func AddTree(aw *Writer, root string) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	parent := filepath.Dir(absRoot)
+
+	return filepath.WalkDir(absRoot, func(filePath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(parent, filePath)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+
+		switch {
+		case info.IsDir():
+			return aw.AddDir(name, info.Mode().Perm(), info.ModTime())
+		case info.Mode().IsRegular():
+			contents, err := os.ReadFile(filePath)
+			if err != nil {
+				return err
+			}
+			return aw.AddFile(name, info.Mode().Perm(), info.ModTime(), contents)
+		default:
+			return fmt.Errorf("unsupported file type: %s", filePath)
+		}
+	})
+}
+
+// Extract writes the selected entries under dest, restoring their modes and modification times.
+// A name selects the entry itself and, for directories, everything below it. With no names
+// every entry is extracted. Files are written through a temporary file renamed into place, and
+// existing files are only replaced when force is set: otherwise nothing is extracted and the
+// error wraps fs.ErrExist.
+func Extract(ar *Reader, dest string, force bool, names ...string) ([]Entry, error) {
+	var selected []Entry
+	for _, e := range ar.Entries() {
+		if matches(e.Name, names) {
+			selected = append(selected, e)
+		}
+	}
+	for _, name := range names {
+		if !selects(name, selected) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+		}
+	}
+
+	if !force {
+		for _, e := range selected {
+			target := filepath.Join(dest, filepath.FromSlash(e.Name))
+			if e.Mode.IsDir() {
+				continue
+			}
+			if _, err := os.Lstat(target); err == nil {
+				return nil, fmt.Errorf("%s: %w", target, fs.ErrExist)
+			} else if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+	}
+
+	var dirs []Entry
+	for _, e := range selected {
+		target := filepath.Join(dest, filepath.FromSlash(e.Name))
+		if e.Mode.IsDir() {
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return nil, err
+			}
+			dirs = append(dirs, e)
+			continue
+		}
+
+		contents, err := ar.ReadEntry(e)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return nil, err
+		}
+		if err := atomicfile.Write(context.Background(), target, contents, e.Mode.Perm(), force); err != nil {
+			return nil, err
+		}
+		if err := os.Chtimes(target, e.ModTime, e.ModTime); err != nil {
+			return nil, err
+		}
+	}
+
+	// Directories last, writing their files would change their modification times
+	for i := len(dirs) - 1; i >= 0; i-- {
+		target := filepath.Join(dest, filepath.FromSlash(dirs[i].Name))
+		if err := os.Chmod(target, dirs[i].Mode.Perm()); err != nil {
+			return nil, err
+		}
+		if err := os.Chtimes(target, dirs[i].ModTime, dirs[i].ModTime); err != nil {
+			return nil, err
+		}
+	}
+	return selected, nil
+}
+
+// matches reports whether name equals one of the selectors or lies below one of them.
+func matches(name string, selectors []string) bool {
+	if len(selectors) == 0 {
+		return true
+	}
+	for _, selector := range selectors {
+		selector = strings.TrimSuffix(path.Clean(selector), "/")
+		if name == selector || strings.HasPrefix(name, selector+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// selects reports whether selector matches at least one of entries.
+func selects(selector string, entries []Entry) bool {
+	for _, e := range entries {
+		if matches(e.Name, []string{selector}) {
+			return true
+		}
+	}
+	return false
+}
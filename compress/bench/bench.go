@@ -0,0 +1,186 @@
+package bench
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+	"github.com/Farber98/cc-solutions/compress/pipeline"
+)
+
+// Codec is a named pair of compression and decompression functions.
+type Codec struct {
+	Name       string
+	Compress   func(data []byte) ([]byte, error)
+	Decompress func(data []byte) ([]byte, error)
+}
+
+// Result holds the measurements of one codec on one file.
+type Result struct {
+	File           string  `json:"file"`
+	Codec          string  `json:"codec"`
+	Size           int     `json:"size"`
+	CompressedSize int     `json:"compressed_size"`
+	Ratio          float64 `json:"ratio"`
+	CompressMBps   float64 `json:"compress_mbps"`
+	DecompressMBps float64 `json:"decompress_mbps"`
+	// Allocated is the number of bytes allocated by one compression and one decompression,
+	// garbage included. The peak heap use is at most that much above the heap before them.
+	Allocated uint64 `json:"allocated"`
+}
+
+// Codecs returns every coder of this module, the bzip2-style pipeline, and the standard
+// library flate and gzip encoders at their default level for comparison.
+func Codecs() []Codec {
+	bzip, err := pipeline.Parse("bwt,mtf,zrle")
+	if err != nil {
+		panic(err)
+	}
+
+	return []Codec{
+		coder("huffman", &compress.HuffmanCompressor{}, &compress.HuffmanDecompressor{}, nil),
+		coder("adaptive", &compress.AdaptiveCompressor{}, &compress.AdaptiveDecompressor{}, nil),
+		coder("range", &compress.RangeCompressor{}, &compress.RangeDecompressor{}, nil),
+		coder("range-context", &compress.RangeCompressor{Context: true}, &compress.RangeDecompressor{}, nil),
+		coder("word", &compress.WordCompressor{}, &compress.WordDecompressor{}, nil),
+		coder("rle", &compress.RLECompressor{}, &compress.RLEDecompressor{}, nil),
+		coder("bwt+huffman", &compress.HuffmanCompressor{}, &compress.HuffmanDecompressor{}, bzip),
+		{
+			Name: "flate",
+			Compress: func(data []byte) ([]byte, error) {
+				return writeAll(data, func(w io.Writer) (io.WriteCloser, error) {
+					return flate.NewWriter(w, flate.DefaultCompression)
+				})
+			},
+			Decompress: func(data []byte) ([]byte, error) {
+				return io.ReadAll(flate.NewReader(bytes.NewReader(data)))
+			},
+		},
+		{
+			Name: "gzip",
+			Compress: func(data []byte) ([]byte, error) {
+				return writeAll(data, func(w io.Writer) (io.WriteCloser, error) {
+					return gzip.NewWriter(w), nil
+				})
+			},
+			Decompress: func(data []byte) ([]byte, error) {
+				r, err := gzip.NewReader(bytes.NewReader(data))
+				if err != nil {
+					return nil, err
+				}
+				return io.ReadAll(r)
+			},
+		},
+	}
+}
+
+// coder wraps a compressor and decompressor of this module, with optional pipeline stages, as a Codec.
+func coder(name string, c compress.Compressor, d compress.Decompressor, p pipeline.Pipeline) Codec {
+	return Codec{
+		Name: name,
+		Compress: func(data []byte) ([]byte, error) {
+			transformed, err := p.Forward(data)
+			if err != nil {
+				return nil, err
+			}
+			return c.Encode(transformed, nil), nil
+		},
+		Decompress: func(data []byte) ([]byte, error) {
+			decoded, err := d.Decode(data, nil)
+			if err != nil {
+				return nil, err
+			}
+			return p.Inverse(decoded)
+		},
+	}
+}
+
+// writeAll compresses data with the writer returned by newWriter.
+func writeAll(data []byte, newWriter func(w io.Writer) (io.WriteCloser, error)) ([]byte, error) {
+	var buffer bytes.Buffer
+	w, err := newWriter(&buffer)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Run measures codec on data. Compression and decompression are each repeated until they took
+// at least minTime, and the decompressed data is checked against the input.
+func Run(codec Codec, file string, data []byte, minTime time.Duration) (Result, error) {
+	result := Result{File: file, Codec: codec.Name, Size: len(data)}
+
+	// Measure the allocations of a single round trip first
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	compressed, err := codec.Compress(data)
+	if err != nil {
+		return result, fmt.Errorf("%s: error compressing %s: %w", codec.Name, file, err)
+	}
+	decompressed, err := codec.Decompress(compressed)
+	if err != nil {
+		return result, fmt.Errorf("%s: error decompressing %s: %w", codec.Name, file, err)
+	}
+	runtime.ReadMemStats(&after)
+	if !bytes.Equal(decompressed, data) {
+		return result, fmt.Errorf("%s: round trip of %s does not match the input", codec.Name, file)
+	}
+
+	result.Allocated = after.TotalAlloc - before.TotalAlloc
+	result.CompressedSize = len(compressed)
+	if len(data) > 0 {
+		result.Ratio = float64(len(compressed)) / float64(len(data))
+	}
+
+	compressTime, err := measure(minTime, func() error {
+		_, err := codec.Compress(data)
+		return err
+	})
+	if err != nil {
+		return result, fmt.Errorf("%s: error compressing %s: %w", codec.Name, file, err)
+	}
+	decompressTime, err := measure(minTime, func() error {
+		_, err := codec.Decompress(compressed)
+		return err
+	})
+	if err != nil {
+		return result, fmt.Errorf("%s: error decompressing %s: %w", codec.Name, file, err)
+	}
+
+	result.CompressMBps = throughput(len(data), compressTime)
+	result.DecompressMBps = throughput(len(data), decompressTime)
+	return result, nil
+}
+
+// measure runs fn at least once and until minTime has passed, and returns the average duration.
+func measure(minTime time.Duration, fn func() error) (time.Duration, error) {
+	start := time.Now()
+	runs := 0
+	for runs == 0 || time.Since(start) < minTime {
+		if err := fn(); err != nil {
+			return 0, err
+		}
+		runs++
+	}
+	return time.Since(start) / time.Duration(runs), nil
+}
+
+// throughput returns size bytes per duration in MB/s.
+func throughput(size int, duration time.Duration) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	return float64(size) / 1e6 / duration.Seconds()
+}
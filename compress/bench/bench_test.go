@@ -0,0 +1,96 @@
+package bench
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// corpus returns the bundled sample files used to compare codecs.
+func corpus(tb testing.TB) map[string][]byte {
+	paths, err := filepath.Glob("../tests/corpus/*")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	files := make(map[string][]byte)
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			tb.Fatal(err)
+		}
+		files[filepath.Base(path)] = contents
+	}
+	return files
+}
+
+func TestRun(t *testing.T) {
+	data := []byte("the same words over and over, the same words over and over")
+
+	for _, codec := range Codecs() {
+		t.Run(codec.Name, func(t *testing.T) {
+			result, err := Run(codec, "sample", data, 0)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result.Size != len(data) || result.CompressedSize == 0 || result.Ratio <= 0 {
+				t.Errorf("Unexpected sizes: %+v", result)
+			}
+			if result.CompressMBps <= 0 || result.DecompressMBps <= 0 || result.Allocated == 0 {
+				t.Errorf("Unexpected measurements: %+v", result)
+			}
+		})
+	}
+}
+
+func TestRunDetectsMismatch(t *testing.T) {
+	broken := Codec{
+		Name:       "broken",
+		Compress:   func(data []byte) ([]byte, error) { return data, nil },
+		Decompress: func(data []byte) ([]byte, error) { return data[1:], nil },
+	}
+	if _, err := Run(broken, "sample", []byte("abc"), time.Millisecond); err == nil {
+		t.Error("Expected an error for a codec that does not round trip")
+	}
+}
+
+func BenchmarkCompress(b *testing.B) {
+	for name, contents := range corpus(b) {
+		for _, codec := range Codecs() {
+			b.Run(name+"/"+codec.Name, func(b *testing.B) {
+				b.SetBytes(int64(len(contents)))
+				b.ReportAllocs()
+				size := 0
+				for i := 0; i < b.N; i++ {
+					compressed, err := codec.Compress(contents)
+					if err != nil {
+						b.Fatal(err)
+					}
+					size = len(compressed)
+				}
+				b.ReportMetric(float64(size)/float64(len(contents)), "ratio")
+			})
+		}
+	}
+}
+
+func BenchmarkDecompress(b *testing.B) {
+	for name, contents := range corpus(b) {
+		for _, codec := range Codecs() {
+			compressed, err := codec.Compress(contents)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.Run(name+"/"+codec.Name, func(b *testing.B) {
+				b.SetBytes(int64(len(contents)))
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					if _, err := codec.Decompress(compressed); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
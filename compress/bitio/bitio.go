@@ -0,0 +1,116 @@
+package bitio
+
+import (
+	"bufio"
+	"io"
+)
+
+// Writer packs bits MSB first into bytes and writes them to an underlying writer.
+type Writer struct {
+	w     *bufio.Writer
+	cur   byte
+	nbits int
+	count int64
+}
+
+// NewWriter creates a new bit writer on top of w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// WriteBit writes a single bit, any non zero value is written as 1.
+func (bw *Writer) WriteBit(bit uint) error {
+	if bit != 0 {
+		bw.cur |= 1 << (7 - bw.nbits)
+	}
+	bw.nbits++
+	bw.count++
+	if bw.nbits == 8 {
+		if err := bw.w.WriteByte(bw.cur); err != nil {
+			return err
+		}
+		bw.cur = 0
+		bw.nbits = 0
+	}
+	return nil
+}
+
+// WriteBits writes the n lowest bits of value, most significant first.
+func (bw *Writer) WriteBits(value uint64, n int) error {
+	for i := n - 1; i >= 0; i-- {
+		if err := bw.WriteBit(uint(value>>i) & 1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BitsWritten returns the number of bits written so far.
+func (bw *Writer) BitsWritten() int64 {
+	return bw.count
+}
+
+// Flush pads the last partial byte with zeros and flushes the underlying writer.
+func (bw *Writer) Flush() error {
+	if bw.nbits > 0 {
+		if err := bw.w.WriteByte(bw.cur); err != nil {
+			return err
+		}
+		bw.cur = 0
+		bw.nbits = 0
+	}
+	return bw.w.Flush()
+}
+
+// Reader reads bits MSB first from an underlying reader.
+type Reader struct {
+	r     io.ByteReader
+	cur   byte
+	nbits int
+	count int64
+}
+
+// NewReader creates a new bit reader on top of r.
+func NewReader(r io.Reader) *Reader {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Reader{r: br}
+}
+
+// ReadBit reads a single bit. It returns io.EOF once the underlying reader is exhausted.
+func (br *Reader) ReadBit() (uint, error) {
+	if br.nbits == 0 {
+		b, err := br.r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		br.cur = b
+		br.nbits = 8
+	}
+	br.nbits--
+	br.count++
+	return uint(br.cur>>br.nbits) & 1, nil
+}
+
+// ReadBits reads n bits and returns them as the lowest bits of the result.
+func (br *Reader) ReadBits(n int) (uint64, error) {
+	var value uint64
+	for i := 0; i < n; i++ {
+		bit, err := br.ReadBit()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		value = value<<1 | uint64(bit)
+	}
+	return value, nil
+}
+
+// BitsRead returns the number of bits read so far.
+func (br *Reader) BitsRead() int64 {
+	return br.count
+}
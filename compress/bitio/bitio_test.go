@@ -0,0 +1,77 @@
+package bitio
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestWriterPacksMSBFirst(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+
+	// 0b101 followed by 0b00001 and a single 1 bit: 101000011 -> 10100001 1(0000000)
+	if err := w.WriteBits(0b101, 3); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteBits(0b00001, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteBit(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{0b10100001, 0b10000000}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		t.Errorf("Expected %08b, got %08b", expected, buf.Bytes())
+	}
+	if w.BitsWritten() != 9 {
+		t.Errorf("Expected 9 bits written, got %d", w.BitsWritten())
+	}
+}
+
+func TestReaderRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	values := []struct {
+		value uint64
+		n     int
+	}{{1, 1}, {0x1ff, 9}, {0, 4}, {0xabcd, 16}}
+	for _, v := range values {
+		if err := w.WriteBits(v.value, v.n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewReader(&buf)
+	for _, v := range values {
+		got, err := r.ReadBits(v.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != v.value {
+			t.Errorf("Expected %x, got %x", v.value, got)
+		}
+	}
+}
+
+func TestReaderEOF(t *testing.T) {
+	r := NewReader(bytes.NewReader([]byte{0xff}))
+	if _, err := r.ReadBits(8); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadBit(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+
+	r = NewReader(bytes.NewReader([]byte{0xff}))
+	if _, err := r.ReadBits(12); err != io.ErrUnexpectedEOF {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
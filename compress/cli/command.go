@@ -1,8 +1,29 @@
 package cli
 
-import "io"
+import (
+	"context"
+	"io"
+)
+
+// Streams holds the standard input and outputs available to a command.
+type Streams struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+	// Context is done when the command should stop, e.g. on SIGINT. Nil means it never is.
+	Context context.Context
+}
+
+// Ctx returns the context of the streams, or a context that is never done when there is none.
+func (s Streams) Ctx() context.Context {
+	if s.Context == nil {
+		return context.Background()
+	}
+	return s.Context
+}
 
 // Command defines the interface for a CLI command.
 type Command interface {
-	Execute(out io.Writer) error
+	// Execute runs the command with the arguments that follow its name.
+	Execute(args []string, streams Streams) error
 }
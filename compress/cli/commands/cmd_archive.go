@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/Farber98/cc-solutions/compress/archive"
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+// CmdArchive implements the Command interface for the archive command.
+type CmdArchive struct{}
+
+// Execute runs the archive command. It packs a file or directory tree into <path>.archive.
+func (c *CmdArchive) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go archive [-o path] [-f] [-v] [path]")
+
+	// Parse flags
+	fs := newFlagSet("archive", streams)
+	output := fs.String("o", "", "write the archive to this path, - for standard output")
+	force := fs.Bool("f", false, "overwrite an existing archive")
+	verbose := fs.Bool("v", false, "report sizes and compression ratio on standard error")
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	if fs.NArg() != 1 {
+		return usage
+	}
+
+	root := filepath.Clean(fs.Arg(0))
+
+	var buffer bytes.Buffer
+	aw := archive.NewWriter(&buffer)
+	if err := archive.AddTree(aw, root); err != nil {
+		return fmt.Errorf("error archiving %s: %w", root, err)
+	}
+	if err := aw.Close(); err != nil {
+		return fmt.Errorf("error writing archive directory: %w", err)
+	}
+
+	// The tree is always kept, it is not a single input file
+	options := outputOptions{output: *output, force: *force, keep: true}
+	outputPath, err := options.write(streams, root, root+".archive", buffer.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if *verbose {
+		size := uint64(0)
+		for _, e := range aw.Entries() {
+			size += e.Size
+		}
+		reportRatio(streams, root, int(size), buffer.Len())
+	}
+	if outputPath != stdio {
+		fmt.Fprintln(streams.Out, outputPath)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+func TestCmdArchive_ListExtract(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "docs")
+	os.MkdirAll(filepath.Join(src, "guide"), 0755)
+	os.WriteFile(filepath.Join(src, "readme.txt"), []byte("read me\n"), 0644)
+	os.WriteFile(filepath.Join(src, "guide", "intro.txt"), []byte("introduction\n"), 0644)
+
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("archive", []string{src}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	archivePath := src + ".archive"
+	if strings.TrimSpace(out.String()) != archivePath {
+		t.Errorf("Expected output %q, got %q", archivePath, out.String())
+	}
+
+	out.Reset()
+	if err := cli.ExecuteCommand("list", []string{archivePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, name := range []string{"docs\n", "docs/readme.txt\n", "docs/guide/intro.txt\n"} {
+		if !strings.Contains(out.String(), name) {
+			t.Errorf("Expected listing to contain %q, got %q", name, out.String())
+		}
+	}
+
+	dest := t.TempDir()
+	out.Reset()
+	if err := cli.ExecuteCommand("extract", []string{"-dir", dest, archivePath, "docs/guide/intro.txt"}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	contents, err := os.ReadFile(filepath.Join(dest, "docs", "guide", "intro.txt"))
+	if err != nil || string(contents) != "introduction\n" {
+		t.Errorf("Expected extracted file, got %q %v", contents, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "docs", "readme.txt")); err == nil {
+		t.Error("Expected readme.txt not to be extracted")
+	}
+
+	// Extracted files are not overwritten without -f
+	err = cli.ExecuteCommand("extract", []string{"-dir", dest, archivePath}, streams)
+	if err == nil || !strings.Contains(err.Error(), "use -f to overwrite it") {
+		t.Errorf("Expected an error for the existing file, got %v", err)
+	}
+	if err := cli.ExecuteCommand("extract", []string{"-dir", dest, "-f", archivePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := cli.ExecuteCommand("archive", []string{src}, streams); err == nil {
+		t.Error("Expected an error when the archive already exists")
+	}
+}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Farber98/cc-solutions/compress/bench"
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+// CmdBench implements the Command interface for the bench command.
+type CmdBench struct{}
+
+// Execute runs the bench command. It runs every codec over the files of a directory and
+// reports ratio, throughput and allocations next to the standard library flate and gzip.
+func (c *CmdBench) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go bench [-time duration] [-json] [dirPath]")
+
+	// Parse flags
+	fs := newFlagSet("bench", streams)
+	minTime := fs.Duration("time", 100*time.Millisecond, "minimum time spent compressing and decompressing each file")
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	if fs.NArg() > 1 {
+		return usage
+	}
+	dir := "tests/corpus"
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+
+	paths, err := samplePaths(dir)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no files found in %s", dir)
+	}
+
+	var results []bench.Result
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading file: %w", err)
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			name = filepath.Base(path)
+		}
+
+		for _, codec := range bench.Codecs() {
+			result, err := bench.Run(codec, name, contents, *minTime)
+			if err != nil {
+				return err
+			}
+			results = append(results, result)
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(streams.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "File\tCodec\tSize\tCompressed\tRatio\tCompress MB/s\tDecompress MB/s\tAllocated KB")
+	for _, r := range results {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.3f\t%.2f\t%.2f\t%d\n", r.File, r.Codec, r.Size, r.CompressedSize, r.Ratio, r.CompressMBps, r.DecompressMBps, (r.Allocated+1023)/1024)
+	}
+	return w.Flush()
+}
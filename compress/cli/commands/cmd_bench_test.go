@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/bench"
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+func TestCmdBench_Execute(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "text.txt"), []byte(strings.Repeat("benchmark me ", 20)), 0644)
+	os.WriteFile(filepath.Join(dir, "data.json"), []byte(`{"a":[1,2,3],"b":"c"}`), 0644)
+
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("bench", []string{"-time", "0", dir}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, expected := range []string{"Compress MB/s", "text.txt", "data.json", "huffman", "gzip", "flate"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Expected output to contain %q, got %q", expected, out.String())
+		}
+	}
+
+	streams, out, _ = testStreams(nil)
+	if err := cli.ExecuteCommand("bench", []string{"-time", "0", "-json", dir}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var results []bench.Result
+	if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+		t.Fatalf("Expected JSON output, got %v", err)
+	}
+	if len(results) != 2*len(bench.Codecs()) {
+		t.Errorf("Expected a result per file and codec, got %d", len(results))
+	}
+}
+
+func TestCmdBench_Errors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{name: "Too_many_args", args: []string{"a", "b"}, expectedError: "usage: go run main.go bench"},
+		{name: "Missing_dir", args: []string{"missing"}, expectedError: "error reading samples"},
+		{name: "Empty_dir", args: []string{t.TempDir()}, expectedError: "no files found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, _ := testStreams(nil)
+			err := cli.ExecuteCommand("bench", tc.args, streams)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+// CmdCat implements the Command interface for the cat command.
+type CmdCat struct{}
+
+// Execute runs the cat command. It writes the decompressed content of every file to standard
+// output, one after the other, like zcat. Files of concatenated frames are decoded back to back.
+func (c *CmdCat) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go cat [-dict path] [-ref path] [-passphrase-env name] [-passphrase-file path] [filePath|-]...")
+
+	// Parse flags
+	fs := newFlagSet("cat", streams)
+	var decode decodeOptions
+	decode.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	if fs.NArg() < 1 {
+		return usage
+	}
+
+	if err := decode.load(); err != nil {
+		return err
+	}
+
+	for _, filePath := range fs.Args() {
+		contents, err := readInput(filePath, streams)
+		if err != nil {
+			return err
+		}
+
+		// Every file is verified before any of it is written
+		decodedText, err := decodeFile(contents, &decode)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+		if _, err := streams.Out.Write(decodedText); err != nil {
+			return fmt.Errorf("error writing to standard output: %w", err)
+		}
+	}
+	return nil
+}
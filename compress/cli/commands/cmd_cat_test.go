@@ -0,0 +1,104 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+	"github.com/Farber98/cc-solutions/compress/container"
+)
+
+// compressed returns data compressed with the given compress flags.
+func compressed(t *testing.T, data []byte, flags ...string) []byte {
+	t.Helper()
+	streams, out, _ := testStreams(data)
+	if err := cli.ExecuteCommand("compress", append(flags, "-"), streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return out.Bytes()
+}
+
+func TestCmdCat_Execute(t *testing.T) {
+	// Rotated log segments, each compressed on its own and appended to one file
+	segments := [][]byte{[]byte("first segment\n"), []byte("second segment\n"), {}, []byte("last segment\n")}
+	var appended []byte
+	for i, segment := range segments {
+		coder := []string{"huffman", "range", "adaptive", "blocks"}[i]
+		appended = append(appended, compressed(t, segment, "-coder", coder)...)
+	}
+	logPath := writeTempFile(t, "app.log.compressed", appended)
+	otherPath := writeTempFile(t, "other.compressed", compressed(t, []byte("another file\n"), "-stages", "bwt,mtf,zrle"))
+
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("-cat", []string{logPath, otherPath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "first segment\nsecond segment\nlast segment\nanother file\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	// decompress handles concatenated frames the same way
+	streams, out, _ = testStreams(nil)
+	if err := cli.ExecuteCommand("decompress", []string{"-c", logPath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out.String() != "first segment\nsecond segment\nlast segment\n" {
+		t.Errorf("Expected the segments back to back, got %q", out.String())
+	}
+}
+
+func TestCmdCat_Errors(t *testing.T) {
+	first := compressed(t, []byte("first"))
+	second := compressed(t, []byte("second"))
+
+	damaged := append(append([]byte{}, first...), second...)
+	damaged[len(damaged)-1] ^= 0xFF
+
+	testCases := []struct {
+		name          string
+		contents      []byte
+		expectedError string
+	}{
+		{name: "Trailing_garbage", contents: append(append([]byte{}, first...), "garbage"...), expectedError: "unexpected data after the last frame"},
+		{name: "Truncated_member", contents: append(append([]byte{}, first...), second[:len(second)-2]...), expectedError: "truncated frame"},
+		{name: "Damaged_member", contents: damaged, expectedError: fmt.Sprintf("frame at byte %d", len(first))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := writeTempFile(t, "input.compressed", tc.contents)
+			streams, out, _ := testStreams(nil)
+			err := cli.ExecuteCommand("cat", []string{filePath}, streams)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+			if out.Len() != 0 {
+				t.Errorf("Expected nothing written for a corrupt file, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestCmdCat_Unsized(t *testing.T) {
+	// Frames written before concatenation was supported run to the end of the file
+	source := []byte("old frame")
+	var buffer bytes.Buffer
+	header := container.Header{Method: container.MethodHuffman, Length: uint64(len(source)), Checksum: container.Checksum(source)}
+	if err := container.WriteHeader(&buffer, header); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	buffer.Write((&compress.HuffmanCompressor{}).Encode(source, nil))
+
+	filePath := writeTempFile(t, "old.compressed", buffer.Bytes())
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("cat", []string{filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out.String() != string(source) {
+		t.Errorf("Expected %q, got %q", source, out.String())
+	}
+}
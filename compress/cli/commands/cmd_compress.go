@@ -1,63 +1,210 @@
 package commands
 
 import (
+	"flag"
 	"fmt"
-	"io"
 	"os"
+	"strconv"
 
+	"github.com/Farber98/cc-solutions/compress/cli"
 	compress "github.com/Farber98/cc-solutions/compress/compression"
-	"github.com/Farber98/cc-solutions/compress/file"
-	"github.com/Farber98/cc-solutions/compress/frequency"
-	"github.com/Farber98/cc-solutions/compress/huffman"
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/dictionary"
+	"github.com/Farber98/cc-solutions/compress/encryption"
+	"github.com/Farber98/cc-solutions/compress/pipeline"
+	"github.com/Farber98/cc-solutions/compress/preset"
 )
 
-// CmdCompress implements the Command interface for the -compress command.
+// CmdCompress implements the Command interface for the compress command.
 type CmdCompress struct{}
 
-// Execute runs the -count command.
-func (c *CmdCompress) Execute(out io.Writer) error {
-	// Check if file name was provided
-	if len(os.Args) < 3 {
-		return fmt.Errorf("usage: go run main.go compress [filePath]")
+// Execute runs the compress command.
+func (c *CmdCompress) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go compress [-1..-9|-auto] [-coder huffman|adaptive|range|word|seekable|blocks|rle] [-context] [-stages bwt,mtf,zrle] [-dict path] [-delta -ref path] [-encrypt] [-passphrase-env name] [-passphrase-file path] [-progress] [-o path] [-c] [-f] [-k] [-v] [filePath|-]...")
+
+	// Parse flags
+	fs := newFlagSet("compress", streams)
+	coder := fs.String("coder", "huffman", "entropy coder: huffman, adaptive, range, word, seekable, blocks or rle")
+	context := fs.Bool("context", false, "use the adaptive order-1 context model with the range coder")
+	stages := fs.String("stages", "", "comma separated transforms applied before the coder, e.g. bwt,mtf,zrle")
+	dictPath := fs.String("dict", "", "code the data with a dictionary built by train instead of storing a table")
+	var levels [preset.MaxLevel + 1]*bool
+	for n := preset.MinLevel; n <= preset.MaxLevel; n++ {
+		levels[n] = fs.Bool(strconv.Itoa(n), false, fmt.Sprintf("compression level %d, from 1 (fastest) to 9 (smallest)", n))
+	}
+	auto := fs.Bool("auto", false, "pick the coder and stages for every file from a sample of it")
+	delta := fs.Bool("delta", false, "encode the files as copies from the -ref file plus literals")
+	refPath := fs.String("ref", "", "reference file for -delta, usually the previous version")
+	encrypt := fs.Bool("encrypt", false, "encrypt the frame with AES-GCM and a key derived from the passphrase")
+	var passphrase passphraseOptions
+	passphrase.register(fs)
+	showProgress := fs.Bool("progress", false, "report bytes processed, ratio and ETA on standard error")
+	var options outputOptions
+	options.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	if fs.NArg() < 1 {
+		return usage
+	}
+	if err := options.validate(fs.Args()); err != nil {
+		return err
 	}
 
-	filePath := os.Args[2]
+	dict, err := readDictionary(*dictPath)
+	if err != nil {
+		return err
+	}
+	p, err := pipeline.Parse(*stages)
+	if err != nil {
+		return err
+	}
+	chosen := preset.Preset{Coder: *coder, Context: *context, Stages: p}
+	level, err := selectLevel(fs, levels[:], *auto)
+	if err != nil {
+		return err
+	}
+	if level > 0 {
+		chosen, _ = preset.Level(level)
+	}
+	method, compressor, err := newPresetCompressor(chosen, dict)
+	if err != nil {
+		return err
+	}
+	if *delta || *refPath != "" {
+		if method, compressor, err = newDeltaCompressor(fs, *delta, *refPath); err != nil {
+			return err
+		}
+	}
+	var key []byte
+	if *encrypt {
+		if key, err = passphrase.read(); err != nil {
+			return err
+		}
+		if len(key) == 0 {
+			return fmt.Errorf("-encrypt needs a passphrase, set $%s or use -passphrase-file", passphrase.envName())
+		}
+	}
 
-	// Create an instance of DefaultFile
-	f := &file.DefaultFile{}
+	for _, filePath := range fs.Args() {
+		// Read file contents
+		contents, err := readInput(filePath, streams)
+		if err != nil {
+			return err
+		}
 
-	// Read file contents
-	contents, err := f.ReadFileContents(filePath)
-	if err != nil {
-		return fmt.Errorf("error reading file: %w", err)
+		if *auto {
+			chosen = preset.Auto(contents)
+			if method, compressor, err = newPresetCompressor(chosen, nil); err != nil {
+				return err
+			}
+			if options.verbose && streams.Err != nil {
+				fmt.Fprintf(streams.Err, "%s: auto picked %v\n", filePath, chosen)
+			}
+		}
+
+		// Encode the stages, the coder payload and the checksum into a frame. The coder takes the
+		// whole file in one call, so the progress is shown when the file starts and when it is done.
+		var report *progress
+		if *showProgress {
+			report = newProgress(streams.Err, filePath, len(contents), false)
+			report.begin()
+		}
+		frame, err := encodeFrame(streams.Ctx(), method, compressor, chosen.Stages, contents)
+		if err != nil {
+			report.stop()
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+		if *encrypt {
+			if frame, err = encryption.Encrypt(frame, key, encryption.DefaultParams); err != nil {
+				report.stop()
+				return err
+			}
+		}
+
+		if _, err := options.write(streams, filePath, filePath+".compressed", frame); err != nil {
+			report.stop()
+			return err
+		}
+		report.finish(len(frame))
+		if options.verbose {
+			reportRatio(streams, filePath, len(contents), len(frame))
+		}
 	}
+	return nil
+}
 
-	// Calculate character frequencies
-	calculator := &frequency.DefaultCalculator{}
-	frequencies := calculator.CalculateFrequencies(contents)
+// selectLevel returns the level chosen with -1 to -9, 0 when none was. Levels and -auto choose
+// the coder and stages themselves, so they cannot be combined with the flags that set them.
+func selectLevel(fs *flag.FlagSet, levels []*bool, auto bool) (int, error) {
+	level := 0
+	for n, set := range levels {
+		if set == nil || !*set {
+			continue
+		}
+		if level > 0 {
+			return 0, fmt.Errorf("-%d and -%d cannot be used together", level, n)
+		}
+		level = n
+	}
+	if level > 0 && auto {
+		return 0, fmt.Errorf("-%d and -auto cannot be used together", level)
+	}
 
-	// Construct Huffman tree
-	h := &huffman.DefaultHuffmanCoding{}
-	huffmanTree := h.BuildHuffmanTree(frequencies)
+	if level > 0 || auto {
+		var err error
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "coder", "context", "stages", "dict":
+				err = fmt.Errorf("-%s cannot be used with a level or -auto", f.Name)
+			}
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return level, nil
+}
 
-	// Assign codes
-	codeTable := make(map[byte]string)
-	h.AssignCodes(huffmanTree, "", codeTable)
+// newDeltaCompressor returns the frame method and compressor for -delta. The reference takes
+// the place of the coder and stages, so the flags that choose them cannot be used.
+func newDeltaCompressor(fs *flag.FlagSet, delta bool, refPath string) (container.Method, compress.Compressor, error) {
+	if !delta {
+		return 0, nil, fmt.Errorf("-ref can only be used with -delta")
+	}
+	if refPath == "" {
+		return 0, nil, fmt.Errorf("-delta needs a reference file, use -ref")
+	}
 
-	// Write header with codeTable
-	err = f.WriteCodeTableOnHeader(codeTable, filePath+".compressed")
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		// Level flags are the numbers 1 to 9
+		_, parseErr := strconv.Atoi(f.Name)
+		switch {
+		case f.Name == "coder", f.Name == "context", f.Name == "stages", f.Name == "dict", f.Name == "auto", parseErr == nil:
+			err = fmt.Errorf("-%s cannot be used with -delta", f.Name)
+		}
+	})
 	if err != nil {
-		return fmt.Errorf("error writing header: %w", err)
+		return 0, nil, err
 	}
 
-	// Encode and write compressed data
-	compressor := &compress.DefaultCompressor{}
-	encodedText := compressor.Encode(contents, codeTable)
-	err = f.WriteTextAfterHeader(encodedText, filePath+".compressed")
+	reference, err := os.ReadFile(refPath)
 	if err != nil {
-		return fmt.Errorf("error writing encoded text: %w", err)
+		return 0, nil, fmt.Errorf("error reading reference: %w", err)
 	}
+	return container.MethodDelta, &compress.DeltaCompressor{Reference: reference}, nil
+}
 
-	fmt.Printf(filePath + ".compressed")
-	return nil
+// newPresetCompressor returns the frame method and compressor of a preset, checking that its
+// stages can be used with its coder.
+func newPresetCompressor(p preset.Preset, dict *dictionary.Dictionary) (container.Method, compress.Compressor, error) {
+	method, compressor, err := newCompressor(p.Coder, p.Context, dict)
+	if err != nil {
+		return 0, nil, err
+	}
+	if (method == container.MethodSeekable || method == container.MethodBlocks) && len(p.Stages) > 0 {
+		return 0, nil, fmt.Errorf("-stages cannot be used with the %v coder", method)
+	}
+	return method, compressor, nil
 }
@@ -2,34 +2,246 @@ package commands
 
 import (
 	"bytes"
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
-	"github.com/Farber98/cc-solutions/compress/file"
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/container"
 )
 
 func TestCmdCompress_Execute(t *testing.T) {
-	// Create a temporary test file with data
 	data := []byte("abbcaabbccc")
+	filePath := writeTempFile(t, "input.txt", data)
 
-	// Create a temporary test file with data
-	f := &file.DefaultFile{}
-	filePath, cleanup, err := f.CreateTempFileWithData(data)
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected no output, got %q", out.String())
+	}
+
+	// The input is replaced by the compressed file
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Error("Expected input to be removed")
+	}
+	compressed, err := os.ReadFile(filePath + ".compressed")
 	if err != nil {
 		t.Fatal(err)
 	}
-	defer cleanup()
+	if !container.IsFramed(compressed) {
+		t.Error("Expected a framed compressed file")
+	}
+}
 
-	// Set os.Args to include the compress flag and the temporary file path
-	os.Args = []string{"", "compress", filePath}
+func TestCmdCompress_Options(t *testing.T) {
+	data := []byte("options options options")
 
-	// Create an instance of CmdCompress
-	cmd := &CmdCompress{}
+	t.Run("Keep", func(t *testing.T) {
+		filePath := writeTempFile(t, "input.txt", data)
+		streams, _, _ := testStreams(nil)
+		if err := cli.ExecuteCommand("compress", []string{"-k", filePath}, streams); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := os.Stat(filePath); err != nil {
+			t.Errorf("Expected input to be kept, got %v", err)
+		}
+	})
 
-	// Execute the command
-	var buf bytes.Buffer
-	err = cmd.Execute(&buf)
-	if err != nil {
+	t.Run("Output", func(t *testing.T) {
+		filePath := writeTempFile(t, "input.txt", data)
+		outputPath := filePath + ".custom"
+		streams, _, _ := testStreams(nil)
+		if err := cli.ExecuteCommand("compress", []string{"-o", outputPath, filePath}, streams); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := os.Stat(outputPath); err != nil {
+			t.Errorf("Expected output at %s, got %v", outputPath, err)
+		}
+	})
+
+	t.Run("Stdout", func(t *testing.T) {
+		filePath := writeTempFile(t, "input.txt", data)
+		streams, out, _ := testStreams(nil)
+		if err := cli.ExecuteCommand("compress", []string{"-c", filePath}, streams); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !container.IsFramed(out.Bytes()) {
+			t.Error("Expected compressed data on standard output")
+		}
+		if _, err := os.Stat(filePath); err != nil {
+			t.Errorf("Expected input to be kept with -c, got %v", err)
+		}
+		if _, err := os.Stat(filePath + ".compressed"); !os.IsNotExist(err) {
+			t.Error("Expected no compressed file with -c")
+		}
+	})
+
+	t.Run("Stdin", func(t *testing.T) {
+		streams, out, _ := testStreams(data)
+		if err := cli.ExecuteCommand("compress", []string{"-"}, streams); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		decoded, err := decodeFile(out.Bytes(), &decodeOptions{})
+		if err != nil || !bytes.Equal(decoded, data) {
+			t.Errorf("Expected %q from standard input, got %q (%v)", data, decoded, err)
+		}
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		filePath := writeTempFile(t, "input.txt", data)
+		os.WriteFile(filePath+".compressed", []byte("existing"), 0644)
+
+		streams, _, _ := testStreams(nil)
+		err := cli.ExecuteCommand("compress", []string{filePath}, streams)
+		if err == nil || !strings.Contains(err.Error(), "use -f to overwrite") {
+			t.Fatalf("Expected overwrite error, got %v", err)
+		}
+		if existing, _ := os.ReadFile(filePath + ".compressed"); string(existing) != "existing" {
+			t.Error("Expected existing output to be untouched")
+		}
+		if _, err := os.Stat(filePath); err != nil {
+			t.Errorf("Expected input to be kept after a failure, got %v", err)
+		}
+
+		if err := cli.ExecuteCommand("compress", []string{"-f", filePath}, streams); err != nil {
+			t.Fatalf("Expected no error with -f, got %v", err)
+		}
+	})
+
+	t.Run("Output_is_input", func(t *testing.T) {
+		inputs := map[string][]byte{"compress": data, "decompress": compressed(t, data)}
+		for cmd, input := range inputs {
+			filePath := writeTempFile(t, "input.txt", input)
+			sameFile := filepath.Join(filepath.Dir(filePath), ".", "input.txt")
+
+			streams, _, _ := testStreams(nil)
+			err := cli.ExecuteCommand(cmd, []string{"-f", "-o", sameFile, filePath}, streams)
+			if err == nil || !strings.Contains(err.Error(), "is the input file") {
+				t.Fatalf("Expected %s to refuse to write over its input, got %v", cmd, err)
+			}
+			if contents, _ := os.ReadFile(filePath); !bytes.Equal(contents, input) {
+				t.Fatalf("Expected the input to survive %s, got %q", cmd, contents)
+			}
+		}
+	})
+
+	t.Run("Verbose", func(t *testing.T) {
+		filePath := writeTempFile(t, "input.txt", data)
+		streams, _, errOut := testStreams(nil)
+		if err := cli.ExecuteCommand("compress", []string{"-v", "-k", filePath}, streams); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !strings.Contains(errOut.String(), "23 -> ") || !strings.Contains(errOut.String(), "% of original") {
+			t.Errorf("Expected ratio report, got %q", errOut.String())
+		}
+	})
+}
+
+func TestCmdCompress_Errors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{name: "No_file", args: []string{}, expectedError: "usage: go run main.go compress"},
+		{name: "Unknown_flag", args: []string{"-nope", "file"}, expectedError: "usage: go run main.go compress"},
+		{name: "Unknown_coder", args: []string{"-coder", "zip", "file"}, expectedError: "unknown coder: zip"},
+		{name: "Unknown_stage", args: []string{"-stages", "lz", "file"}, expectedError: "unknown stage: lz"},
+		{name: "Output_and_stdout", args: []string{"-o", "x", "-c", "file"}, expectedError: "-o and -c cannot be used together"},
+		{name: "Output_with_many_inputs", args: []string{"-o", "x", "a", "b"}, expectedError: "-o can only be used with a single input"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, _ := testStreams(nil)
+			err := cli.ExecuteCommand("compress", tc.args, streams)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestCmdCompress_Levels(t *testing.T) {
+	var logs bytes.Buffer
+	for i := 0; i < 3000; i++ {
+		fmt.Fprintf(&logs, "2024-01-01T00:00:%02d GET /api/items/%d 200\n", i%60, i%50)
+	}
+	data := logs.Bytes()
+
+	testCases := []struct {
+		name           string
+		flags          []string
+		expectedMethod container.Method
+		expectedStages int
+	}{
+		{name: "Level_1", flags: []string{"-1"}, expectedMethod: container.MethodRange},
+		{name: "Level_3", flags: []string{"-3"}, expectedMethod: container.MethodRange, expectedStages: 3},
+		{name: "Level_9", flags: []string{"-9"}, expectedMethod: container.MethodRange, expectedStages: 3},
+		{name: "Auto", flags: []string{"-auto"}, expectedMethod: container.MethodRange, expectedStages: 3},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, compressed, _ := testStreams(data)
+			if err := cli.ExecuteCommand("compress", append(tc.flags, "-"), streams); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			// The chosen pipeline is in the header, decompress needs no flags
+			header, err := container.ReadHeader(bytes.NewReader(compressed.Bytes()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header.Method != tc.expectedMethod || len(header.Stages) != tc.expectedStages {
+				t.Errorf("Expected %v with %d stages, got %v with %d", tc.expectedMethod, tc.expectedStages, header.Method, len(header.Stages))
+			}
+
+			streams, out, _ := testStreams(compressed.Bytes())
+			if err := cli.ExecuteCommand("decompress", []string{"-"}, streams); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), data) {
+				t.Errorf("Expected the original data back")
+			}
+		})
+	}
+}
+
+func TestCmdCompress_LevelErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		flags         []string
+		expectedError string
+	}{
+		{name: "Two_levels", flags: []string{"-1", "-9"}, expectedError: "-1 and -9 cannot be used together"},
+		{name: "Level_and_auto", flags: []string{"-auto", "-6"}, expectedError: "-6 and -auto cannot be used together"},
+		{name: "Level_and_coder", flags: []string{"-6", "-coder", "huffman"}, expectedError: "-coder cannot be used with a level or -auto"},
+		{name: "Auto_and_stages", flags: []string{"-auto", "-stages", "bwt"}, expectedError: "-stages cannot be used with a level or -auto"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, _ := testStreams([]byte("data"))
+			err := cli.ExecuteCommand("compress", append(tc.flags, "-"), streams)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestCmdCompress_AutoVerbose(t *testing.T) {
+	streams, _, errOut := testStreams([]byte("key = value\n"))
+	if err := cli.ExecuteCommand("compress", []string{"-auto", "-v", "-"}, streams); err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
+	if !strings.Contains(errOut.String(), "-: auto picked -coder range -context") {
+		t.Errorf("Expected the picked preset to be reported, got %q", errOut.String())
+	}
 }
@@ -2,59 +2,107 @@ package commands
 
 import (
 	"fmt"
-	"io"
-	"os"
+	"strings"
 
+	"github.com/Farber98/cc-solutions/compress/cli"
 	compress "github.com/Farber98/cc-solutions/compress/compression"
-	"github.com/Farber98/cc-solutions/compress/file"
 )
 
-// CmdDecompress implements the Command interface for the -decompress command.
+// CmdDecompress implements the Command interface for the decompress command.
 type CmdDecompress struct{}
 
-// Execute runs the -decompress command.
-func (c *CmdDecompress) Execute(out io.Writer) error {
-	// Check if file name was provided
-	if len(os.Args) < 3 {
-		return fmt.Errorf("usage: go run main.go decompress [filePath]")
+// Execute runs the decompress command.
+func (c *CmdDecompress) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go decompress [-recover] [-dict path] [-ref path] [-passphrase-env name] [-passphrase-file path] [-progress] [-o path] [-c] [-f] [-k] [-v] [filePath|-]...")
+
+	// Parse flags
+	fs := newFlagSet("decompress", streams)
+	recovery := fs.Bool("recover", false, "skip damaged blocks, write what is intact and report the damaged byte ranges")
+	showProgress := fs.Bool("progress", false, "report bytes processed, ratio and ETA on standard error")
+	var decode decodeOptions
+	decode.register(fs)
+	var options outputOptions
+	options.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	if fs.NArg() < 1 {
+		return usage
+	}
+	if err := options.validate(fs.Args()); err != nil {
+		return err
 	}
 
-	filePath := os.Args[2]
+	if err := decode.load(); err != nil {
+		return err
+	}
 
-	// Create an instance of DefaultFile
-	f := &file.DefaultFile{}
+	damagedFiles := 0
+	for _, filePath := range fs.Args() {
+		// Read file contents
+		contents, err := readInput(filePath, streams)
+		if err != nil {
+			return err
+		}
 
-	// Read reversed look up table from header
-	reverseLookupCodeTable, err := f.ReadReverseLookupCodesTableFromHeader(filePath)
-	if err != nil {
-		return fmt.Errorf("error reading header: %w", err)
-	}
+		// Decode and verify before anything is written
+		var report *progress
+		if *showProgress {
+			report = newProgress(streams.Err, filePath, len(contents), true)
+		}
+		var decodedText []byte
+		var damaged []compress.ByteRange
+		if *recovery {
+			decodedText, damaged, err = recoverFile(contents, &decode)
+		} else {
+			decodedText, err = decodeFrames(streams.Ctx(), contents, &decode, report.update)
+		}
+		if err != nil {
+			report.stop()
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
 
-	// Read encoded content after header
-	encodedText, err := f.ReadTextAfterHeader(filePath)
-	if err != nil {
-		return fmt.Errorf("error reading encoded text: %w", err)
+		output := options
+		if len(damaged) > 0 {
+			// The damaged input may still be repaired by other means, so it is never removed
+			output.keep = true
+			damagedFiles++
+			reportDamage(streams, filePath, len(decodedText), damaged)
+		}
+		if _, err := output.write(streams, filePath, decompressedPath(filePath), decodedText); err != nil {
+			report.stop()
+			return err
+		}
+		report.finish(len(decodedText))
+		if options.verbose {
+			reportRatio(streams, filePath, len(decodedText), len(contents))
+		}
 	}
 
-	// Decode the encoded text using the reverse lookup table
-	decompressor := &compress.DefaultDecompressor{}
-	decodedText, err := decompressor.Decode(encodedText, reverseLookupCodeTable)
-	if err != nil {
-		return fmt.Errorf("error decoding text: %w", err)
+	if damagedFiles > 0 {
+		return fmt.Errorf("%d of %d files recovered with damaged ranges", damagedFiles, fs.NArg())
 	}
+	return nil
+}
 
-	// Write the decoded text to a new file
-	outputPath := filePath + ".decompressed"
-	newFile, err := f.CreateNewFile(outputPath)
-	if err != nil {
-		return fmt.Errorf("error creating decompressed file: %w", err)
+// reportDamage writes the damaged byte ranges of a recovered file to the error stream.
+func reportDamage(streams cli.Streams, name string, length int, damaged []compress.ByteRange) {
+	if streams.Err == nil {
+		return
 	}
-	defer newFile.Close() // Ensure the file is closed after writing
 
-	err = f.WriteTextAfterHeader(decodedText, outputPath)
-	if err != nil {
-		return fmt.Errorf("error writing decoded text: %w", err)
+	lost := uint64(0)
+	for _, r := range damaged {
+		lost += r.End - r.Start
+		fmt.Fprintf(streams.Err, "%s: bytes %d-%d are damaged (%d bytes)\n", name, r.Start, r.End-1, r.End-r.Start)
 	}
+	fmt.Fprintf(streams.Err, "%s: recovered %d of %d bytes\n", name, uint64(length)-lost, length)
+}
 
-	return nil
+// decompressedPath returns the default output path for a compressed file.
+func decompressedPath(filePath string) string {
+	if trimmed := strings.TrimSuffix(filePath, ".compressed"); trimmed != filePath && trimmed != "" {
+		return trimmed
+	}
+	return filePath + ".decompressed"
 }
@@ -1 +1,183 @@
 package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+func TestCmdDecompress_FramedRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name  string
+		flags []string
+	}{
+		{name: "Huffman", flags: []string{}},
+		{name: "Adaptive", flags: []string{"-coder", "adaptive"}},
+		{name: "Range", flags: []string{"--coder", "range"}},
+		{name: "RangeContext", flags: []string{"--coder", "range", "-context"}},
+		{name: "Word", flags: []string{"-coder", "word"}},
+		{name: "Seekable", flags: []string{"-coder", "seekable"}},
+		{name: "Blocks", flags: []string{"-coder", "blocks"}},
+		{name: "RLE", flags: []string{"-coder", "rle"}},
+		{name: "HuffmanPipeline", flags: []string{"-stages", "bwt,mtf,zrle"}},
+		{name: "RangePipeline", flags: []string{"-coder", "range", "-stages", "bwt,mtf"}},
+	}
+
+	data := []byte("abbcaabbccc\nwith a newline")
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := writeTempFile(t, "input.txt", data)
+
+			streams, _, _ := testStreams(nil)
+			if err := cli.ExecuteCommand("compress", append(tc.flags, filePath), streams); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if err := cli.ExecuteCommand("decompress", []string{filePath + ".compressed"}, streams); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			// The original name is restored and the compressed file removed
+			decoded, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(decoded, data) {
+				t.Errorf("Expected %q, got %q", data, decoded)
+			}
+			if _, err := os.Stat(filePath + ".compressed"); !os.IsNotExist(err) {
+				t.Error("Expected compressed file to be removed")
+			}
+		})
+	}
+}
+
+func TestCmdDecompress_Streams(t *testing.T) {
+	data := []byte("standard input and output")
+
+	streams, compressed, _ := testStreams(data)
+	if err := cli.ExecuteCommand("compress", []string{"-"}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	streams, out, _ := testStreams(compressed.Bytes())
+	if err := cli.ExecuteCommand("decompress", []string{"-"}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("Expected %q, got %q", data, out.Bytes())
+	}
+}
+
+func TestCmdDecompress_OutputPaths(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "a.txt.compressed", expected: "a.txt"},
+		{input: "a.bin", expected: "a.bin.decompressed"},
+		{input: ".compressed", expected: ".compressed.decompressed"},
+	}
+
+	for _, tc := range testCases {
+		if got := decompressedPath(tc.input); got != tc.expected {
+			t.Errorf("Expected %q for %q, got %q", tc.expected, tc.input, got)
+		}
+	}
+}
+
+func TestCmdDecompress_CorruptKeepsInput(t *testing.T) {
+	filePath := writeTempFile(t, "input.txt", []byte("corrupt me please"))
+
+	streams, _, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{filePath}, streams); err != nil {
+		t.Fatal(err)
+	}
+	compressed, _ := os.ReadFile(filePath + ".compressed")
+	compressed[len(compressed)-1] ^= 0xff
+	os.WriteFile(filePath+".compressed", compressed, 0644)
+
+	err := cli.ExecuteCommand("decompress", []string{filePath + ".compressed"}, streams)
+	if err == nil || !strings.Contains(err.Error(), filePath+".compressed") {
+		t.Fatalf("Expected an error naming the file, got %v", err)
+	}
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Error("Expected no output for a corrupt file")
+	}
+	if _, err := os.Stat(filePath + ".compressed"); err != nil {
+		t.Errorf("Expected corrupt input to be kept, got %v", err)
+	}
+}
+
+func TestCmdDecompress_Recover(t *testing.T) {
+	var logs bytes.Buffer
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&logs, "request %d served\n", i)
+	}
+	data := logs.Bytes()
+
+	testCases := []struct {
+		name          string
+		coder         string
+		damage        bool
+		expectedError string
+		expectedLog   string
+	}{
+		{name: "Intact", coder: "blocks"},
+		{name: "Damaged_block", coder: "blocks", damage: true, expectedError: "1 of 1 files recovered with damaged ranges", expectedLog: "bytes 65536-131071 are damaged (65536 bytes)"},
+		{name: "No_sync_markers", coder: "huffman", damage: true, expectedError: "only files compressed with -coder blocks can be recovered"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := writeTempFile(t, "app.log", data)
+			streams, _, _ := testStreams(nil)
+			if err := cli.ExecuteCommand("compress", []string{"-coder", tc.coder, filePath}, streams); err != nil {
+				t.Fatal(err)
+			}
+			if tc.damage {
+				// Flip a bit in the middle of the second block
+				compressed, _ := os.ReadFile(filePath + ".compressed")
+				compressed[len(compressed)*3/10] ^= 0x08
+				os.WriteFile(filePath+".compressed", compressed, 0644)
+
+				if err := cli.ExecuteCommand("decompress", []string{"-k", filePath + ".compressed"}, streams); err == nil {
+					t.Fatalf("Expected decompress without -recover to fail")
+				}
+			}
+
+			streams, out, errOut := testStreams(nil)
+			err := cli.ExecuteCommand("decompress", []string{"-recover", "-c", filePath + ".compressed"}, streams)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if !bytes.Equal(out.Bytes(), data) {
+					t.Errorf("Expected the original data")
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Fatalf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+			if tc.expectedLog == "" {
+				return
+			}
+
+			if !strings.Contains(errOut.String(), tc.expectedLog) {
+				t.Errorf("Expected report containing %q, got %q", tc.expectedLog, errOut.String())
+			}
+			recovered := out.Bytes()
+			if len(recovered) != len(data) {
+				t.Fatalf("Expected %d bytes, got %d", len(data), len(recovered))
+			}
+			if !bytes.Equal(recovered[:65536], data[:65536]) || !bytes.Equal(recovered[131072:], data[131072:]) {
+				t.Errorf("Expected the intact blocks to be recovered")
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+func TestCmdCompress_Delta(t *testing.T) {
+	var old, updated bytes.Buffer
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&old, "record %d value %d\n", i, i*7)
+		if i == 2500 {
+			updated.WriteString("a new record\n")
+		}
+		fmt.Fprintf(&updated, "record %d value %d\n", i, i*7)
+	}
+	oldPath := writeTempFile(t, "data.v1", old.Bytes())
+	newPath := writeTempFile(t, "data.v2", updated.Bytes())
+
+	streams, _, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{"-delta", "--ref", oldPath, "-k", newPath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	patch, err := os.ReadFile(newPath + ".compressed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(patch) > 100 {
+		t.Errorf("Expected a patch of at most 100 bytes, got %d", len(patch))
+	}
+
+	// undelta is decompress, which needs the same reference
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("-undelta", []string{"-ref", oldPath, "-c", newPath + ".compressed"}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), updated.Bytes()) {
+		t.Errorf("Expected the new file back")
+	}
+
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{name: "No_reference", args: []string{"-c"}, expectedError: "data was compressed against a reference, use -ref"},
+		{name: "Wrong_reference", args: []string{"-ref", newPath, "-c"}, expectedError: "reference does not match"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, _ := testStreams(nil)
+			err := cli.ExecuteCommand("undelta", append(tc.args, newPath+".compressed"), streams)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestCmdCompress_DeltaErrors(t *testing.T) {
+	refPath := writeTempFile(t, "ref", []byte("reference"))
+
+	testCases := []struct {
+		name          string
+		flags         []string
+		expectedError string
+	}{
+		{name: "No_reference", flags: []string{"-delta"}, expectedError: "-delta needs a reference file, use -ref"},
+		{name: "Reference_without_delta", flags: []string{"-ref", refPath}, expectedError: "-ref can only be used with -delta"},
+		{name: "Coder", flags: []string{"-delta", "-ref", refPath, "-coder", "range"}, expectedError: "-coder cannot be used with -delta"},
+		{name: "Level", flags: []string{"-delta", "-ref", refPath, "-9"}, expectedError: "-9 cannot be used with -delta"},
+		{name: "Missing_reference", flags: []string{"-delta", "-ref", refPath + ".missing"}, expectedError: "error reading reference"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, _ := testStreams([]byte("data"))
+			err := cli.ExecuteCommand("compress", append(tc.flags, "-"), streams)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/encryption"
+)
+
+func TestCmdCompress_Encrypt(t *testing.T) {
+	data := []byte("quarterly export for our partners")
+	filePath := writeTempFile(t, "export.csv", data)
+	t.Setenv("HZ_PASSPHRASE", "correct horse battery staple")
+
+	streams, _, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{"-encrypt", filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	encrypted, _ := os.ReadFile(filePath + ".compressed")
+	if !container.IsEncrypted(encrypted) {
+		t.Fatal("Expected an encrypted frame")
+	}
+
+	// The passphrase can also come from a file, a trailing newline is ignored
+	passphraseFile := writeTempFile(t, "passphrase", []byte("correct horse battery staple\n"))
+	streams, out, _ := testStreams(nil)
+	err := cli.ExecuteCommand("decompress", []string{"-c", "-passphrase-env", "UNSET_VARIABLE", "-passphrase-file", passphraseFile, filePath + ".compressed"}, streams)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("Expected %q, got %q", data, out.Bytes())
+	}
+
+	streams, out, _ = testStreams(nil)
+	if err := cli.ExecuteCommand("test", []string{filePath + ".compressed"}, streams); err != nil || !strings.Contains(out.String(), "OK") {
+		t.Errorf("Expected OK, got %q (%v)", out.String(), err)
+	}
+}
+
+func TestCmdDecompress_EncryptedErrors(t *testing.T) {
+	data := []byte("nothing to see here")
+	filePath := writeTempFile(t, "secret.txt", data)
+	t.Setenv("HZ_PASSPHRASE", "right")
+
+	streams, _, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{"-encrypt", filePath}, streams); err != nil {
+		t.Fatal(err)
+	}
+	compressedPath := filePath + ".compressed"
+
+	t.Run("Wrong_passphrase", func(t *testing.T) {
+		t.Setenv("HZ_PASSPHRASE", "wrong")
+		err := cli.ExecuteCommand("decompress", []string{compressedPath}, streams)
+		if !errors.Is(err, encryption.ErrAuthentication) {
+			t.Errorf("Expected ErrAuthentication, got %v", err)
+		}
+		// Nothing is written before the tag verifies
+		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+			t.Error("Expected no output for a failed authentication")
+		}
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		encrypted, _ := os.ReadFile(compressedPath)
+		encrypted[len(encrypted)-1] ^= 1
+		tamperedPath := filepath.Join(t.TempDir(), "tampered.compressed")
+		os.WriteFile(tamperedPath, encrypted, 0644)
+
+		err := cli.ExecuteCommand("decompress", []string{tamperedPath}, streams)
+		if !errors.Is(err, encryption.ErrAuthentication) {
+			t.Errorf("Expected ErrAuthentication, got %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(filepath.Dir(tamperedPath), "tampered")); !os.IsNotExist(err) {
+			t.Error("Expected no output for tampered data")
+		}
+	})
+
+	t.Run("No_passphrase", func(t *testing.T) {
+		t.Setenv("HZ_PASSPHRASE", "")
+		err := cli.ExecuteCommand("decompress", []string{"-c", compressedPath}, streams)
+		if !errors.Is(err, container.ErrEncrypted) || !strings.Contains(err.Error(), "$HZ_PASSPHRASE") {
+			t.Errorf("Expected ErrEncrypted naming the variable, got %v", err)
+		}
+	})
+}
+
+func TestCmdCompress_EncryptWithoutPassphrase(t *testing.T) {
+	t.Setenv("HZ_PASSPHRASE", "")
+	streams, _, _ := testStreams([]byte("data"))
+	err := cli.ExecuteCommand("compress", []string{"-encrypt", "-"}, streams)
+	if err == nil || !strings.Contains(err.Error(), "-encrypt needs a passphrase") {
+		t.Errorf("Expected missing passphrase error, got %v", err)
+	}
+
+	err = cli.ExecuteCommand("compress", []string{"-encrypt", "-passphrase-file", "missing", "-"}, streams)
+	if err == nil || !strings.Contains(err.Error(), "error reading passphrase") {
+		t.Errorf("Expected passphrase file error, got %v", err)
+	}
+}
+
+func TestCmdDecompress_EncryptedConcatenation(t *testing.T) {
+	a := writeTempFile(t, "a.txt", []byte("first segment\n"))
+	b := writeTempFile(t, "b.txt", []byte("second segment\n"))
+	t.Setenv("HZ_PASSPHRASE", "rotate")
+
+	// Encrypted frames record their size, so they can be followed by more frames
+	streams, compressed, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{"-encrypt", "-k", "-c", a, b}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	streams, _, _ = testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{"-k", a}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	plain, _ := os.ReadFile(a + ".compressed")
+	joined := writeTempFile(t, "joined.compressed", append(compressed.Bytes(), plain...))
+
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("decompress", []string{"-c", joined}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := "first segment\nsecond segment\nfirst segment\n"; out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+}
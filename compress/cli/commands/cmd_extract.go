@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Farber98/cc-solutions/compress/archive"
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+// CmdExtract implements the Command interface for the extract command.
+type CmdExtract struct{}
+
+// Execute runs the extract command. It extracts the named entries, or all of them, from an archive.
+func (c *CmdExtract) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go extract [-dir directory] [-f] [archivePath|-] [name...]")
+
+	// Parse flags
+	fs := newFlagSet("extract", streams)
+	dir := fs.String("dir", ".", "directory to extract into")
+	force := fs.Bool("f", false, "overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	if fs.NArg() < 1 {
+		return usage
+	}
+
+	ar, err := openArchive(fs.Arg(0), streams)
+	if err != nil {
+		return err
+	}
+
+	extracted, err := archive.Extract(ar, *dir, *force, fs.Args()[1:]...)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%w, use -f to overwrite it", err)
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range extracted {
+		fmt.Fprintln(streams.Out, e.Name)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/Farber98/cc-solutions/compress/archive"
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+// CmdList implements the Command interface for the list command.
+type CmdList struct{}
+
+// Execute runs the list command. It prints the central directory of an archive.
+func (c *CmdList) Execute(args []string, streams cli.Streams) error {
+	// Check if archive was provided
+	if len(args) < 1 {
+		return fmt.Errorf("usage: go run main.go list [archivePath|-]")
+	}
+
+	ar, err := openArchive(args[0], streams)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range ar.Entries() {
+		fmt.Fprintf(streams.Out, "%s %10d %10d %s %s\n", e.Mode, e.Size, e.CompressedSize, e.ModTime.Format(time.DateTime), e.Name)
+	}
+	return nil
+}
+
+// openArchive reads an archive and its central directory.
+func openArchive(archivePath string, streams cli.Streams) (*archive.Reader, error) {
+	contents, err := readInput(archivePath, streams)
+	if err != nil {
+		return nil, err
+	}
+	return archive.NewReader(bytes.NewReader(contents), int64(len(contents)))
+}
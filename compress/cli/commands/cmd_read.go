@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+)
+
+// CmdRead implements the Command interface for the read command.
+type CmdRead struct{}
+
+// Execute runs the read command. It writes a byte range of a seekable file to standard output,
+// decoding only the blocks that hold it.
+func (c *CmdRead) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go read [-offset n] [-length n] [filePath|-]")
+
+	// Parse flags
+	fs := newFlagSet("read", streams)
+	offset := fs.Int64("offset", 0, "offset of the first byte, negative to count from the end")
+	length := fs.Int64("length", -1, "number of bytes to read, -1 to read to the end")
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	if fs.NArg() != 1 {
+		return usage
+	}
+
+	r, size, closer, err := openReaderAt(fs.Arg(0), streams)
+	if err != nil {
+		return err
+	}
+	defer closer()
+
+	reader, err := compress.NewSeekReader(r, size)
+	if err != nil {
+		return err
+	}
+
+	whence := io.SeekStart
+	if *offset < 0 {
+		whence = io.SeekEnd
+	}
+	if _, err := reader.Seek(*offset, whence); err != nil {
+		return err
+	}
+
+	var src io.Reader = reader
+	if *length >= 0 {
+		src = io.LimitReader(reader, *length)
+	}
+	if _, err := io.Copy(streams.Out, src); err != nil {
+		return fmt.Errorf("error reading range: %w", err)
+	}
+	return nil
+}
+
+// openReaderAt opens a file for random access. Standard input cannot seek, so it is read into memory.
+func openReaderAt(path string, streams cli.Streams) (io.ReaderAt, int64, func(), error) {
+	if path == stdio {
+		contents, err := readInput(path, streams)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		return bytes.NewReader(contents), int64(len(contents)), func() {}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, nil, fmt.Errorf("error reading file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, 0, nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return f, info.Size(), func() { f.Close() }, nil
+}
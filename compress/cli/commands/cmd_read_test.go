@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+func TestCmdRead_Execute(t *testing.T) {
+	var logs bytes.Buffer
+	for i := 0; i < 20000; i++ {
+		fmt.Fprintf(&logs, "request %d served\n", i)
+	}
+	data := logs.Bytes()
+	filePath := writeTempFile(t, "app.log", data)
+
+	streams, _, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{"-coder", "seekable", filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	testCases := []struct {
+		name     string
+		args     []string
+		expected []byte
+	}{
+		{name: "Range", args: []string{"-offset", "100000", "-length", "50"}, expected: data[100000:100050]},
+		{name: "Tail", args: []string{"-offset", "-25"}, expected: data[len(data)-25:]},
+		{name: "Whole", args: []string{}, expected: data},
+		{name: "Past_the_end", args: []string{"-offset", "999999999"}, expected: []byte{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, out, _ := testStreams(nil)
+			if err := cli.ExecuteCommand("read", append(tc.args, filePath+".compressed"), streams); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !bytes.Equal(out.Bytes(), tc.expected) {
+				t.Errorf("Expected %q, got %q", tc.expected, out.Bytes())
+			}
+		})
+	}
+
+	// Seekable files decompress like any other
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("decompress", []string{"-c", filePath + ".compressed"}, streams); err != nil || !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("Expected full decompression to work, got %v", err)
+	}
+}
+
+func TestCmdRead_NotSeekable(t *testing.T) {
+	streams, compressed, _ := testStreams([]byte("plain huffman"))
+	if err := cli.ExecuteCommand("compress", []string{"-"}, streams); err != nil {
+		t.Fatal(err)
+	}
+
+	streams, _, _ = testStreams(compressed.Bytes())
+	err := cli.ExecuteCommand("read", []string{"-"}, streams)
+	if err == nil || !strings.Contains(err.Error(), "frame has no seek index") {
+		t.Errorf("Expected an error for a frame without index, got %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/stats"
+)
+
+// CmdStats implements the Command interface for the stats command. It is also registered as count.
+type CmdStats struct{}
+
+// Execute runs the stats command. It reports the byte frequencies of a file together with how
+// well static Huffman coding can compress it.
+func (c *CmdStats) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go stats [-json] [filePath|-]")
+
+	// Parse flags
+	fs := newFlagSet("stats", streams)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	// Check if file name was provided
+	if fs.NArg() < 1 {
+		return usage
+	}
+
+	// Read file contents
+	contents, err := readInput(fs.Arg(0), streams)
+	if err != nil {
+		return err
+	}
+
+	analyzer := &stats.DefaultAnalyzer{}
+	report := analyzer.Analyze(contents)
+
+	if *asJSON {
+		encoder := json.NewEncoder(streams.Out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	return writeReport(streams, report)
+}
+
+// writeReport prints a report as a summary followed by the frequency and code table.
+func writeReport(streams cli.Streams, report stats.Report) error {
+	verdict := "worth compressing"
+	if !report.Worthwhile {
+		verdict = "not worth compressing"
+	}
+
+	w := tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Size:\t%d bytes\n", report.Size)
+	fmt.Fprintf(w, "Distinct symbols:\t%d\n", report.Distinct)
+	fmt.Fprintf(w, "Entropy:\t%.4f bits/byte\n", report.Entropy)
+	fmt.Fprintf(w, "Average code length:\t%.4f bits/byte\n", report.AverageCodeLength)
+	fmt.Fprintf(w, "Theoretical size:\t%d bytes\n", report.TheoreticalSize)
+	fmt.Fprintf(w, "Achieved size:\t%d bytes (%d payload + %d header)\n", report.AchievedSize, report.PayloadSize, report.HeaderSize)
+	fmt.Fprintf(w, "Ratio:\t%.1f%% (%s)\n", report.Ratio*100, verdict)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(report.Symbols) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(streams.Out)
+	w = tabwriter.NewWriter(streams.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Symbol\tByte\tCount\tPercent\tCode")
+	for _, symbol := range report.Symbols {
+		fmt.Fprintf(w, "%s\t0x%02x\t%d\t%.2f%%\t%s\n", symbol.Display, symbol.Byte, symbol.Count, symbol.Percent, symbol.Code)
+	}
+	return w.Flush()
+}
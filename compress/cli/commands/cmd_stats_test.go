@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/stats"
+)
+
+func TestCmdStats_Success(t *testing.T) {
+	data := []byte("abbcaabbccc\n") // 4 different chars, one of them non-printable.
+	filePath := writeTempFile(t, "count.txt", data)
+
+	for _, name := range []string{"stats", "count"} {
+		t.Run(name, func(t *testing.T) {
+			// Use a buffer to capture the output instead of using os.Stdout
+			streams, out, _ := testStreams(nil)
+			err := cli.ExecuteCommand(name, []string{filePath}, streams)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			for _, expected := range []string{"Size:                 12 bytes", "Entropy:", "Average code length:", "header)", `"\n"    0x0a  1      8.33%`} {
+				if !strings.Contains(out.String(), expected) {
+					t.Errorf("Expected output to contain %q, got %q", expected, out.String())
+				}
+			}
+			// Sorted by count, b and c come before a
+			if strings.Index(out.String(), `"b"`) > strings.Index(out.String(), `"a"`) {
+				t.Errorf("Expected symbols sorted by count, got %q", out.String())
+			}
+		})
+	}
+}
+
+func TestCmdStats_JSON(t *testing.T) {
+	streams, out, _ := testStreams([]byte("aaaaaaab"))
+	if err := cli.ExecuteCommand("stats", []string{"-json", "-"}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var report stats.Report
+	if err := json.Unmarshal(out.Bytes(), &report); err != nil {
+		t.Fatalf("Expected JSON output, got %v", err)
+	}
+	if report.Size != 8 || len(report.Symbols) != 2 || report.Symbols[0].Display != `"a"` {
+		t.Errorf("Unexpected report: %+v", report)
+	}
+}
+
+func TestCmdStats_NoFilePathProvided(t *testing.T) {
+	// Use a buffer to capture the output instead of using os.Stdout
+	streams, _, _ := testStreams(nil)
+	err := cli.ExecuteCommand("-stats", []string{}, streams)
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	// Check if error message matches the expected message
+	expectedErrorMessage := "usage: go run main.go stats [-json] [filePath|-]"
+	if err.Error() != expectedErrorMessage {
+		t.Errorf("Expected error message %q, got %q", expectedErrorMessage, err.Error())
+	}
+}
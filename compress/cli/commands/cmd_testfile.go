@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/container"
+)
+
+// ErrCorrupt is returned by the test command when a file fails to decode or verify.
+var ErrCorrupt = errors.New("corrupt compressed file")
+
+// CmdTest implements the Command interface for the test command.
+type CmdTest struct{}
+
+// Execute runs the test command. It decodes every file to io.Discard and reports OK or CORRUPT.
+func (c *CmdTest) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go test [-dict path] [-ref path] [-passphrase-env name] [-passphrase-file path] [filePath|-]...")
+
+	// Parse flags
+	fs := newFlagSet("test", streams)
+	var decode decodeOptions
+	decode.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	// Check if file name was provided
+	if fs.NArg() < 1 {
+		return usage
+	}
+
+	if err := decode.load(); err != nil {
+		return err
+	}
+
+	corrupt := 0
+	for _, filePath := range fs.Args() {
+		contents, err := readInput(filePath, streams)
+		if err != nil {
+			return err
+		}
+
+		decodedText, err := decodeFile(contents, &decode)
+		if err != nil {
+			fmt.Fprintf(streams.Out, "%s: CORRUPT (%v)\n", filePath, err)
+			corrupt++
+			continue
+		}
+		if _, err := io.Discard.Write(decodedText); err != nil {
+			return err
+		}
+
+		if !container.IsFramed(contents) {
+			fmt.Fprintf(streams.Out, "%s: OK (legacy format, no checksum)\n", filePath)
+			continue
+		}
+		fmt.Fprintf(streams.Out, "%s: OK\n", filePath)
+	}
+
+	if corrupt > 0 {
+		return fmt.Errorf("%w: %d of %d files", ErrCorrupt, corrupt, fs.NArg())
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+func TestCmdTest_Execute(t *testing.T) {
+	data := []byte("abbcaabbccc\nintegrity check")
+	filePath := writeTempFile(t, "input.txt", data)
+
+	streams, _, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	compressed, err := os.ReadFile(filePath + ".compressed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name     string
+		corrupt  func([]byte) []byte
+		expected string
+	}{
+		{name: "OK", corrupt: func(b []byte) []byte { return b }, expected: "OK"},
+		{name: "Flipped_payload_bit", corrupt: func(b []byte) []byte { b[len(b)-2] ^= 0x10; return b }, expected: "CORRUPT"},
+		{name: "Flipped_checksum_bit", corrupt: func(b []byte) []byte { b[6] ^= 0x01; return b }, expected: "CORRUPT"},
+		{name: "Truncated", corrupt: func(b []byte) []byte { return b[:len(b)-3] }, expected: "CORRUPT"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testPath := writeTempFile(t, "test.compressed", tc.corrupt(append([]byte{}, compressed...)))
+
+			streams, out, _ := testStreams(nil)
+			err = cli.ExecuteCommand("-test", []string{testPath}, streams)
+			if !strings.Contains(out.String(), tc.expected) {
+				t.Errorf("Expected output to contain %q, got %q", tc.expected, out.String())
+			}
+			if tc.expected == "OK" && err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+			if tc.expected == "CORRUPT" && !errors.Is(err, ErrCorrupt) {
+				t.Errorf("Expected ErrCorrupt, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCmdTest_NoFilePathProvided(t *testing.T) {
+	streams, _, _ := testStreams(nil)
+	err := cli.ExecuteCommand("test", []string{}, streams)
+	if err == nil || err.Error() != "usage: go run main.go test [-dict path] [-ref path] [-passphrase-env name] [-passphrase-file path] [filePath|-]..." {
+		t.Errorf("Expected usage error, got %v", err)
+	}
+}
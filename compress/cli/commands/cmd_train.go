@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/dictionary"
+)
+
+// CmdTrain implements the Command interface for the train command.
+type CmdTrain struct{}
+
+// Execute runs the train command. It builds a dictionary from the sample files and directories.
+func (c *CmdTrain) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go train [-o dictPath] [-f] [samplePath|-]...")
+
+	// Parse flags
+	flags := newFlagSet("train", streams)
+	output := flags.String("o", "dictionary.dict", "write the dictionary to this path")
+	force := flags.Bool("f", false, "overwrite an existing dictionary")
+	if err := flags.Parse(args); err != nil {
+		return usage
+	}
+	if flags.NArg() < 1 {
+		return usage
+	}
+
+	var samples [][]byte
+	size := 0
+	for _, samplePath := range flags.Args() {
+		paths, err := samplePaths(samplePath)
+		if err != nil {
+			return err
+		}
+		for _, path := range paths {
+			contents, err := readInput(path, streams)
+			if err != nil {
+				return err
+			}
+			samples = append(samples, contents)
+			size += len(contents)
+		}
+	}
+
+	d := dictionary.Train(samples)
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		return fmt.Errorf("error writing dictionary: %w", err)
+	}
+	if err := writeFile(streams.Ctx(), *output, buf.Bytes(), *force); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(streams.Out, "%s: dictionary %08x trained on %d files, %d bytes\n", *output, d.ID, len(samples), size)
+	return nil
+}
+
+// samplePaths returns path itself, or every regular file below it when it is a directory.
+func samplePaths(path string) ([]string, error) {
+	if path == stdio {
+		return []string{path}, nil
+	}
+
+	var paths []string
+	err := filepath.WalkDir(path, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading samples: %w", err)
+	}
+	return paths, nil
+}
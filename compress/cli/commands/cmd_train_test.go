@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/dictionary"
+)
+
+func TestCmdTrain_DictionaryRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	samples := filepath.Join(dir, "samples")
+	os.MkdirAll(filepath.Join(samples, "nested"), 0755)
+	for i := 0; i < 10; i++ {
+		blob := fmt.Sprintf(`{"id":%d,"name":"user%d","active":true}`, i, i)
+		os.WriteFile(filepath.Join(samples, "nested", fmt.Sprintf("%d.json", i)), []byte(blob), 0644)
+	}
+	dictPath := filepath.Join(dir, "json.dict")
+
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("train", []string{"-o", dictPath, samples}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "trained on 10 files") {
+		t.Errorf("Expected training summary, got %q", out.String())
+	}
+
+	data := []byte(`{"id":42,"name":"user42","active":false}`)
+	filePath := writeTempFile(t, "blob.json", data)
+	if err := cli.ExecuteCommand("compress", []string{"-k", "-dict", dictPath, filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	withDictionary, _ := os.ReadFile(filePath + ".compressed")
+	if err := cli.ExecuteCommand("compress", []string{"-c", filePath}, streams); err != nil {
+		t.Fatal(err)
+	}
+	if len(withDictionary) >= out.Len() {
+		t.Errorf("Expected dictionary output to be smaller than %d bytes, got %d", out.Len(), len(withDictionary))
+	}
+
+	// Decompression needs the dictionary
+	err := cli.ExecuteCommand("decompress", []string{"-c", filePath + ".compressed"}, streams)
+	if err == nil || !strings.Contains(err.Error(), "use -dict") {
+		t.Errorf("Expected an error asking for the dictionary, got %v", err)
+	}
+
+	streams, out, _ = testStreams(nil)
+	if err := cli.ExecuteCommand("decompress", []string{"-c", "-dict", dictPath, filePath + ".compressed"}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), data) {
+		t.Errorf("Expected %q, got %q", data, out.Bytes())
+	}
+
+	if err := cli.ExecuteCommand("test", []string{"-dict", dictPath, filePath + ".compressed"}, streams); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestCmdTrain_WrongDictionary(t *testing.T) {
+	dir := t.TempDir()
+	first, second := filepath.Join(dir, "first.dict"), filepath.Join(dir, "second.dict")
+	streams, _, _ := testStreams([]byte("first corpus"))
+	if err := cli.ExecuteCommand("train", []string{"-o", first, "-"}, streams); err != nil {
+		t.Fatal(err)
+	}
+	streams, _, _ = testStreams([]byte("a rather different second corpus"))
+	if err := cli.ExecuteCommand("train", []string{"-o", second, "-"}, streams); err != nil {
+		t.Fatal(err)
+	}
+
+	streams, compressed, _ := testStreams([]byte("first"))
+	if err := cli.ExecuteCommand("compress", []string{"-dict", first, "-"}, streams); err != nil {
+		t.Fatal(err)
+	}
+	streams, _, _ = testStreams(compressed.Bytes())
+	err := cli.ExecuteCommand("decompress", []string{"-dict", second, "-"}, streams)
+	if !errors.Is(err, dictionary.ErrMismatch) {
+		t.Errorf("Expected ErrMismatch, got %v", err)
+	}
+}
+
+func TestCmdTrain_Errors(t *testing.T) {
+	var valid bytes.Buffer
+	dictionary.Train(nil).WriteTo(&valid)
+	validPath := writeTempFile(t, "valid.dict", valid.Bytes())
+
+	testCases := []struct {
+		name          string
+		command       string
+		args          []string
+		expectedError string
+	}{
+		{name: "No_samples", command: "train", args: []string{}, expectedError: "usage: go run main.go train"},
+		{name: "Missing_samples", command: "train", args: []string{"-o", filepath.Join(t.TempDir(), "x.dict"), "missing"}, expectedError: "error reading samples"},
+		{name: "Missing_dictionary", command: "compress", args: []string{"-dict", "missing.dict", "file"}, expectedError: "error reading dictionary"},
+		{name: "Dictionary_with_range", command: "compress", args: []string{"-coder", "range", "-dict", validPath, "file"}, expectedError: "-dict can only be used with the huffman coder"},
+		{name: "Invalid_dictionary", command: "decompress", args: []string{"-dict", writeTempFile(t, "empty.dict", nil), "file"}, expectedError: "error reading dictionary"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, _ := testStreams(nil)
+			err := cli.ExecuteCommand(tc.command, tc.args, streams)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
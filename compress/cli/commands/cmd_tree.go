@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/frequency"
+	"github.com/Farber98/cc-solutions/compress/huffman"
+)
+
+// CmdTree implements the Command interface for the tree command.
+type CmdTree struct{}
+
+// Execute runs the tree command. It prints the Huffman tree of a file as Graphviz DOT or JSON.
+func (c *CmdTree) Execute(args []string, streams cli.Streams) error {
+	usage := fmt.Errorf("usage: go run main.go tree [-format dot|json] [filePath|-]")
+
+	// Parse flags
+	fs := newFlagSet("tree", streams)
+	format := fs.String("format", "dot", "output format: dot or json")
+	if err := fs.Parse(args); err != nil {
+		return usage
+	}
+	if fs.NArg() < 1 {
+		return usage
+	}
+	if *format != "dot" && *format != "json" {
+		return fmt.Errorf("unknown format: %s", *format)
+	}
+
+	// Read file contents
+	contents, err := readInput(fs.Arg(0), streams)
+	if err != nil {
+		return err
+	}
+
+	// Build the tree exactly like the compressor does
+	calculator := &frequency.DefaultCalculator{}
+	h := &huffman.DefaultHuffmanCoding{}
+	root := h.BuildHuffmanTree(calculator.CalculateFrequencies(contents))
+
+	if *format == "json" {
+		return huffman.WriteJSON(streams.Out, root)
+	}
+	return huffman.WriteDOT(streams.Out, root)
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/huffman"
+)
+
+func TestCmdTree_Execute(t *testing.T) {
+	filePath := writeTempFile(t, "tree.txt", []byte("abbcaabbccc"))
+
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("tree", []string{filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "digraph huffman {") || !strings.Contains(out.String(), `label="11"`) {
+		t.Errorf("Expected DOT output with the root frequency, got %q", out.String())
+	}
+
+	streams, out, _ = testStreams(nil)
+	if err := cli.ExecuteCommand("tree", []string{"-format", "json", filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var root huffman.TreeNode
+	if err := json.Unmarshal(out.Bytes(), &root); err != nil || root.Freq != 11 {
+		t.Errorf("Expected JSON tree with frequency 11, got %q (%v)", out.String(), err)
+	}
+}
+
+func TestCmdTree_EmptyInput(t *testing.T) {
+	streams, out, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("tree", []string{"-"}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out.String() != "digraph huffman {\n\tnode [fontname=\"monospace\"];\n}\n" {
+		t.Errorf("Expected an empty graph, got %q", out.String())
+	}
+}
+
+func TestCmdTree_Errors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedError string
+	}{
+		{name: "No_file", args: []string{}, expectedError: "usage: go run main.go tree"},
+		{name: "Unknown_format", args: []string{"-format", "svg", "file"}, expectedError: "unknown format: svg"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streams, _, _ := testStreams(nil)
+			err := cli.ExecuteCommand("tree", tc.args, streams)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/dictionary"
+	"github.com/Farber98/cc-solutions/compress/encryption"
+	"github.com/Farber98/cc-solutions/compress/file"
+	"github.com/Farber98/cc-solutions/compress/internal/framing"
+	"github.com/Farber98/cc-solutions/compress/pipeline"
+)
+
+// newCompressor returns the frame method and compressor for a -coder name. A dictionary, when
+// given, replaces the table stored by the huffman coder.
+func newCompressor(coder string, context bool, dict *dictionary.Dictionary) (container.Method, compress.Compressor, error) {
+	if dict != nil {
+		if coder != "huffman" {
+			return 0, nil, fmt.Errorf("-dict can only be used with the huffman coder")
+		}
+		return container.MethodDictionary, &compress.DictionaryCompressor{Dictionary: dict}, nil
+	}
+
+	switch coder {
+	case "huffman":
+		return container.MethodHuffman, &compress.HuffmanCompressor{}, nil
+	case "adaptive":
+		return container.MethodAdaptive, &compress.AdaptiveCompressor{}, nil
+	case "range":
+		return container.MethodRange, &compress.RangeCompressor{Context: context}, nil
+	case "word":
+		return container.MethodWord, &compress.WordCompressor{}, nil
+	case "seekable":
+		return container.MethodSeekable, &compress.SeekableCompressor{}, nil
+	case "blocks":
+		return container.MethodBlocks, &compress.BlocksCompressor{}, nil
+	case "rle":
+		return container.MethodRLE, &compress.RLECompressor{}, nil
+	default:
+		return 0, nil, fmt.Errorf("unknown coder: %s", coder)
+	}
+}
+
+// newDecompressor returns the decompressor for a frame method. Dictionary and delta frames need
+// the dictionary or the reference loaded by options.
+func newDecompressor(method container.Method, options *decodeOptions) (compress.LimitedDecompressor, error) {
+	switch method {
+	case container.MethodDictionary:
+		if options.dict == nil {
+			return nil, fmt.Errorf("data was compressed with a dictionary, use -dict")
+		}
+		return &compress.DictionaryDecompressor{Dictionary: options.dict}, nil
+	case container.MethodDelta:
+		if options.refPath == "" {
+			return nil, fmt.Errorf("data was compressed against a reference, use -ref")
+		}
+		return &compress.DeltaDecompressor{Reference: options.ref}, nil
+	default:
+		return framing.NewDecompressor(method)
+	}
+}
+
+// encodeFrame codes contents into a single frame. It stops with the error of ctx once ctx is
+// done.
+func encodeFrame(ctx context.Context, method container.Method, compressor compress.Compressor, p pipeline.Pipeline, contents []byte) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return framing.Encode(method, compressor, p, contents)
+}
+
+// decodeFrame decodes a frame with the coder and stages recorded in its header and verifies
+// the result against the stored length and checksum.
+func decodeFrame(contents []byte, options *decodeOptions) ([]byte, error) {
+	return framing.Decode(contents, func(method container.Method) (compress.LimitedDecompressor, error) {
+		return newDecompressor(method, options)
+	})
+}
+
+// decodeOptions holds the flags needed to read compressed files, shared by decompress and test.
+type decodeOptions struct {
+	dictPath   string
+	refPath    string
+	passphrase passphraseOptions
+
+	// Loaded by load
+	dict *dictionary.Dictionary
+	ref  []byte
+	key  []byte
+}
+
+// register registers the decode flags on fs.
+func (o *decodeOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.dictPath, "dict", "", "dictionary the files were compressed with")
+	fs.StringVar(&o.refPath, "ref", "", "reference file the files were compressed against with -delta")
+	o.passphrase.register(fs)
+}
+
+// load reads the dictionary, the reference and the passphrase named by the flags.
+func (o *decodeOptions) load() error {
+	dict, err := readDictionary(o.dictPath)
+	if err != nil {
+		return err
+	}
+	if o.refPath != "" {
+		if o.ref, err = os.ReadFile(o.refPath); err != nil {
+			return fmt.Errorf("error reading reference: %w", err)
+		}
+	}
+	key, err := o.passphrase.read()
+	if err != nil {
+		return err
+	}
+	o.dict, o.key = dict, key
+	return nil
+}
+
+// decodeFile decodes the contents of a compressed file, framed or with a legacy table header.
+// Concatenated frames are decoded back to back. Encrypted frames are authenticated and decrypted
+// first. Legacy files carry no checksum, so they cannot be verified.
+func decodeFile(contents []byte, options *decodeOptions) ([]byte, error) {
+	return decodeFrames(context.Background(), contents, options, func(done, produced int) {})
+}
+
+// decodeFrames decodes a compressed file like decodeFile and calls report after every frame.
+// It stops with the error of ctx once ctx is done.
+func decodeFrames(ctx context.Context, contents []byte, options *decodeOptions, report func(done, produced int)) ([]byte, error) {
+	if container.IsFramed(contents) {
+		decodedText := []byte{}
+		for offset := 0; offset < len(contents); {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			decoded, size, err := decodeNext(contents[offset:], options)
+			if err != nil && offset > 0 {
+				return nil, fmt.Errorf("frame at byte %d: %w", offset, err)
+			}
+			if err != nil {
+				return nil, err
+			}
+			decodedText = append(decodedText, decoded...)
+			offset += size
+			report(offset, len(decodedText))
+		}
+		return decodedText, nil
+	}
+
+	// Create an instance of DefaultFile
+	f := &file.DefaultFile{}
+
+	// Read reversed look up table from header
+	reverseLookupCodeTable, err := f.ParseReverseLookupCodesTable(contents)
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+
+	// Read encoded content after header
+	encodedText, err := f.ParseTextAfterHeader(contents)
+	if err != nil {
+		return nil, fmt.Errorf("error reading encoded text: %w", err)
+	}
+
+	// Decode the encoded text using the reverse lookup table
+	decompressor := &compress.DefaultDecompressor{}
+	decodedText, err := decompressor.Decode(encodedText, reverseLookupCodeTable)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding text: %w", err)
+	}
+	return decodedText, nil
+}
+
+// decodeNext decodes the frame at the start of contents and returns its size.
+func decodeNext(contents []byte, options *decodeOptions) ([]byte, int, error) {
+	if !container.IsFramed(contents) {
+		return nil, 0, fmt.Errorf("unexpected data after the last frame")
+	}
+	size, err := container.FrameSize(contents)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading header: %w", err)
+	}
+	decodedText, err := decodeMember(contents[:size], options)
+	return decodedText, size, err
+}
+
+// decodeMember decodes a single frame, decrypting it first when it is encrypted.
+func decodeMember(frame []byte, options *decodeOptions) ([]byte, error) {
+	if !container.IsEncrypted(frame) {
+		return decodeFrame(frame, options)
+	}
+	if len(options.key) == 0 {
+		return nil, fmt.Errorf("%w, set $%s or use -passphrase-file", container.ErrEncrypted, options.passphrase.envName())
+	}
+	decrypted, err := encryption.Decrypt(frame, options.key)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFrame(decrypted, options)
+}
+
+// recoverFile decodes a compressed file like decodeFile and, when that fails, recovers what it
+// can frame by frame: frames that decode are kept, and of damaged blocks coder frames the intact
+// blocks are. It returns the damaged ranges of the output, whose bytes are zero. Frames of other
+// coders can only be decoded as a whole.
+func recoverFile(contents []byte, options *decodeOptions) ([]byte, []compress.ByteRange, error) {
+	decodedText, err := decodeFile(contents, options)
+	if err == nil {
+		return decodedText, nil, nil
+	}
+	if container.IsEncrypted(contents) || !container.IsFramed(contents) {
+		// Authenticated encryption is all or nothing, and legacy files have no blocks
+		return nil, nil, err
+	}
+
+	decodedText = []byte{}
+	var damaged []compress.ByteRange
+	for offset := 0; offset < len(contents); {
+		// A damaged header hides where its frame ends, the markers of the rest are scanned
+		size, sizeErr := container.FrameSize(contents[offset:])
+		if sizeErr != nil || !container.IsFramed(contents[offset:]) {
+			size = len(contents) - offset
+		}
+
+		recovered, frameDamage, err := recoverFrame(contents[offset:offset+size], options)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, r := range frameDamage {
+			shift := uint64(len(decodedText))
+			damaged = append(damaged, compress.ByteRange{Start: r.Start + shift, End: r.End + shift})
+		}
+		decodedText = append(decodedText, recovered...)
+		offset += size
+	}
+	return decodedText, damaged, nil
+}
+
+// recoverFrame decodes a frame, or recovers the intact blocks of a damaged blocks coder frame.
+func recoverFrame(frame []byte, options *decodeOptions) ([]byte, []compress.ByteRange, error) {
+	decodedText, err := decodeMember(frame, options)
+	if err == nil {
+		return decodedText, nil, nil
+	}
+	if container.IsEncrypted(frame) {
+		return nil, nil, err
+	}
+
+	reader := bytes.NewReader(frame)
+	header, headerErr := container.ReadHeader(reader)
+	payload, length := frame, uint64(0)
+	if headerErr == nil {
+		if header.Method != container.MethodBlocks || len(header.Stages) > 0 {
+			return nil, nil, fmt.Errorf("%w, only files compressed with -coder blocks can be recovered", err)
+		}
+		payload, length = frame[len(frame)-reader.Len():], header.Length
+	}
+
+	recovered, damaged := compress.Recover(payload, length)
+	if headerErr != nil && len(recovered) == 0 {
+		return nil, nil, err
+	}
+	return recovered, damaged, nil
+}
+
+// readDictionary reads the dictionary file given with -dict, or returns nil when path is empty.
+func readDictionary(path string) (*dictionary.Dictionary, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading dictionary: %w", err)
+	}
+	defer f.Close()
+
+	d, err := dictionary.Read(f)
+	if err != nil {
+		return nil, fmt.Errorf("error reading dictionary %s: %w", path, err)
+	}
+	return d, nil
+}
+
+// defaultPassphraseEnv is the environment variable the passphrase is read from by default.
+const defaultPassphraseEnv = "HZ_PASSPHRASE"
+
+// passphraseOptions holds the flags that select where the encryption passphrase comes from.
+// Passphrases are never taken from the command line, where other users could see them.
+type passphraseOptions struct {
+	env  string
+	file string
+}
+
+// register registers the passphrase flags on fs.
+func (o *passphraseOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.env, "passphrase-env", defaultPassphraseEnv, "environment variable holding the passphrase")
+	fs.StringVar(&o.file, "passphrase-file", "", "file holding the passphrase, overrides -passphrase-env")
+}
+
+// envName returns the environment variable the passphrase is read from.
+func (o *passphraseOptions) envName() string {
+	if o.env == "" {
+		return defaultPassphraseEnv
+	}
+	return o.env
+}
+
+// read returns the passphrase from the file, or from the environment variable when no file is
+// given. A trailing newline in the file is not part of the passphrase. It returns nil when unset.
+func (o *passphraseOptions) read() ([]byte, error) {
+	if o.file == "" {
+		return []byte(os.Getenv(o.envName())), nil
+	}
+
+	contents, err := os.ReadFile(o.file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading passphrase: %w", err)
+	}
+	return bytes.TrimRight(contents, "\r\n"), nil
+}
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+	"github.com/Farber98/cc-solutions/compress/file"
+	"github.com/Farber98/cc-solutions/compress/internal/atomicfile"
+)
+
+// stdio is the path that stands for standard input or standard output.
+const stdio = "-"
+
+// newFlagSet creates a flag set for a command that reports parse errors on the error stream.
+func newFlagSet(name string, streams cli.Streams) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	if streams.Err != nil {
+		fs.SetOutput(streams.Err)
+	} else {
+		fs.SetOutput(io.Discard)
+	}
+	return fs
+}
+
+// readInput reads a file, or standard input when path is "-". The input is read whole, as every
+// coder, adaptive too, is written in a frame that starts with its length and checksum.
+func readInput(path string, streams cli.Streams) ([]byte, error) {
+	if path == stdio {
+		contents, err := io.ReadAll(streams.In)
+		if err != nil {
+			return nil, fmt.Errorf("error reading standard input: %w", err)
+		}
+		return contents, nil
+	}
+
+	// Create an instance of DefaultFile
+	f := &file.DefaultFile{}
+	contents, err := f.ReadFileContents(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	return contents, nil
+}
+
+// outputOptions holds the output flags shared by compress and decompress.
+type outputOptions struct {
+	output  string
+	stdout  bool
+	force   bool
+	keep    bool
+	verbose bool
+}
+
+// register registers the output flags on fs.
+func (o *outputOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.output, "o", "", "write the output to this path, - for standard output")
+	fs.BoolVar(&o.stdout, "c", false, "write the output to standard output and keep the input")
+	fs.BoolVar(&o.force, "f", false, "overwrite existing output files")
+	fs.BoolVar(&o.keep, "k", false, "keep the input file")
+	fs.BoolVar(&o.verbose, "v", false, "report sizes and compression ratio on standard error")
+}
+
+// validate checks the options against the input paths.
+func (o *outputOptions) validate(paths []string) error {
+	if o.output != "" && len(paths) > 1 {
+		return fmt.Errorf("-o can only be used with a single input")
+	}
+	if o.output != "" && o.stdout {
+		return fmt.Errorf("-o and -c cannot be used together")
+	}
+	return nil
+}
+
+// target returns where the output for inputPath goes, "-" meaning standard output.
+func (o *outputOptions) target(inputPath, defaultPath string) string {
+	switch {
+	case o.stdout:
+		return stdio
+	case o.output != "":
+		return o.output
+	case inputPath == stdio:
+		return stdio
+	default:
+		return defaultPath
+	}
+}
+
+// write writes data to the target of inputPath and removes the input unless it has to be kept.
+func (o *outputOptions) write(streams cli.Streams, inputPath, defaultPath string, data []byte) (string, error) {
+	outputPath := o.target(inputPath, defaultPath)
+	if err := checkNotInput(inputPath, outputPath); err != nil {
+		return "", err
+	}
+	if outputPath == stdio {
+		if _, err := streams.Out.Write(data); err != nil {
+			return "", fmt.Errorf("error writing to standard output: %w", err)
+		}
+	} else if err := writeFile(streams.Ctx(), outputPath, data, o.force); err != nil {
+		return "", err
+	}
+
+	// Like gzip, the input is only removed once the output is safely written
+	if !o.keep && !o.stdout && inputPath != stdio && outputPath != stdio {
+		if err := os.Remove(inputPath); err != nil {
+			return "", fmt.Errorf("error removing input: %w", err)
+		}
+	}
+	return outputPath, nil
+}
+
+// checkNotInput fails when outputPath is the input file, which writing would overwrite and then
+// remove.
+func checkNotInput(inputPath, outputPath string) error {
+	if inputPath == stdio || outputPath == stdio {
+		return nil
+	}
+	input, err := os.Stat(inputPath)
+	if err != nil {
+		return nil
+	}
+	if output, err := os.Stat(outputPath); err == nil && os.SameFile(input, output) {
+		return fmt.Errorf("%s is the input file, choose another output", outputPath)
+	}
+	return nil
+}
+
+// writeFile writes data to path like atomicfile.Write, with the mode of new files the commands
+// write.
+func writeFile(ctx context.Context, path string, data []byte, force bool) error {
+	err := atomicfile.Write(ctx, path, data, 0644, force)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%s already exists, use -f to overwrite it", path)
+	}
+	return err
+}
+
+// reportRatio writes the compressed and uncompressed sizes of a file to the error stream.
+func reportRatio(streams cli.Streams, name string, uncompressed, compressed int) {
+	if streams.Err == nil {
+		return
+	}
+
+	ratio := 0.0
+	if uncompressed > 0 {
+		ratio = float64(compressed) / float64(uncompressed) * 100
+	}
+	fmt.Fprintf(streams.Err, "%s: %d -> %d bytes (%.1f%% of original)\n", name, uncompressed, compressed, ratio)
+}
@@ -1,7 +1,9 @@
 package commands
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Farber98/cc-solutions/compress/cli"
@@ -9,10 +11,37 @@ import (
 
 func TestMain(m *testing.M) {
 	// Set up before tests run, register command
-	cli.Register("-count", &CmdCount{})
-	cli.Register("-compress", &CmdCompress{})
-	cli.Register("-decompress", &CmdCompress{})
+	cli.Register("count", &CmdStats{})
+	cli.Register("stats", &CmdStats{})
+	cli.Register("compress", &CmdCompress{})
+	cli.Register("decompress", &CmdDecompress{})
+	cli.Register("undelta", &CmdDecompress{})
+	cli.Register("test", &CmdTest{})
+	cli.Register("read", &CmdRead{})
+	cli.Register("tree", &CmdTree{})
+	cli.Register("train", &CmdTrain{})
+	cli.Register("bench", &CmdBench{})
+	cli.Register("archive", &CmdArchive{})
+	cli.Register("list", &CmdList{})
+	cli.Register("extract", &CmdExtract{})
+	cli.Register("cat", &CmdCat{})
 
 	// Run tests
 	os.Exit(m.Run())
 }
+
+// testStreams returns streams reading from in and capturing both outputs.
+func testStreams(in []byte) (cli.Streams, *bytes.Buffer, *bytes.Buffer) {
+	var out, errOut bytes.Buffer
+	return cli.Streams{In: bytes.NewReader(in), Out: &out, Err: &errOut}, &out, &errOut
+}
+
+// writeTempFile writes data to a new file in a temporary directory and returns its path.
+func writeTempFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	filePath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
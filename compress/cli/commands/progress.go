@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// progressInterval is the shortest time between two progress updates of a file.
+const progressInterval = 250 * time.Millisecond
+
+// progress writes the status of a file being processed to the error stream, on a single line
+// that every update overwrites.
+type progress struct {
+	w     io.Writer
+	name  string
+	total int
+	// decompressing is set when the input is the compressed side.
+	decompressing bool
+	start         time.Time
+	last          time.Time
+	now           func() time.Time
+	// shown is set while an update is on the current line.
+	shown bool
+}
+
+// newProgress returns the progress of a file of total input bytes, or nil when w is nil.
+func newProgress(w io.Writer, name string, total int, decompressing bool) *progress {
+	if w == nil {
+		return nil
+	}
+	p := &progress{w: w, name: name, total: total, decompressing: decompressing, now: time.Now}
+	p.start = p.now()
+	p.last = p.start
+	return p
+}
+
+// begin shows the status before anything is processed, unless there is nothing to process.
+func (p *progress) begin() {
+	if p == nil || p.total == 0 {
+		return
+	}
+	p.shown = true
+	fmt.Fprintf(p.w, "\r%s", p.status(0, 0))
+}
+
+// update reports that done of the total input bytes were processed into produced output bytes,
+// at most once per progressInterval. The final totals are left to finish.
+func (p *progress) update(done, produced int) {
+	if p == nil || done >= p.total || p.now().Sub(p.last) < progressInterval {
+		return
+	}
+	p.last = p.now()
+	p.shown = true
+	fmt.Fprintf(p.w, "\r%s", p.status(done, produced))
+}
+
+// stop ends the line of the last update, so that an error is reported on a line of its own.
+func (p *progress) stop() {
+	if p != nil && p.shown {
+		p.shown = false
+		fmt.Fprintln(p.w)
+	}
+}
+
+// finish reports the final totals and ends the line.
+func (p *progress) finish(produced int) {
+	if p == nil {
+		return
+	}
+	p.shown = false
+	fmt.Fprintf(p.w, "\r%s\n", p.status(p.total, produced))
+}
+
+// status describes the progress, e.g. "app.log: 12.0 of 48.0 MB (25%), ratio 31.2%, ETA 9s".
+// The ratio is the compressed over the original size of what was processed so far.
+func (p *progress) status(done, produced int) string {
+	percent, ratio := 100.0, 0.0
+	if p.total > 0 {
+		percent = float64(done) / float64(p.total) * 100
+	}
+	compressed, original := produced, done
+	if p.decompressing {
+		compressed, original = done, produced
+	}
+	if original > 0 {
+		ratio = float64(compressed) / float64(original) * 100
+	}
+
+	eta := "ETA -"
+	elapsed := p.now().Sub(p.start)
+	switch {
+	case done >= p.total:
+		eta = fmt.Sprintf("done in %s", elapsed.Round(time.Millisecond))
+	case done > 0:
+		remaining := time.Duration(float64(elapsed) * float64(p.total-done) / float64(done))
+		eta = fmt.Sprintf("ETA %s", remaining.Round(time.Second))
+	}
+	return fmt.Sprintf("%s: %.1f of %.1f MB (%.0f%%), ratio %.1f%%, %s", p.name, megabytes(done), megabytes(p.total), percent, ratio, eta)
+}
+
+// megabytes converts a size in bytes to MB.
+func megabytes(n int) float64 {
+	return float64(n) / (1 << 20)
+}
@@ -0,0 +1,125 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Farber98/cc-solutions/compress/cli"
+)
+
+func TestProgress_Status(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := start
+
+	var out bytes.Buffer
+	p := newProgress(&out, "app.log", 48<<20, false)
+	p.now = func() time.Time { return now }
+	p.start, p.last = start, start
+
+	// Updates closer together than the interval are dropped
+	now = start.Add(progressInterval / 2)
+	p.update(1<<20, 1<<19)
+	if out.Len() != 0 {
+		t.Errorf("Expected no update within the interval, got %q", out.String())
+	}
+
+	now = start.Add(3 * time.Second)
+	p.update(12<<20, 4<<20)
+	expected := "\rapp.log: 12.0 of 48.0 MB (25%), ratio 33.3%, ETA 9s"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	// The final totals are only shown by finish
+	out.Reset()
+	now = start.Add(12 * time.Second)
+	p.update(48<<20, 16<<20)
+	if out.Len() != 0 {
+		t.Errorf("Expected no update of the final totals, got %q", out.String())
+	}
+	p.finish(16 << 20)
+	expected = "\rapp.log: 48.0 of 48.0 MB (100%), ratio 33.3%, done in 12s\n"
+	if out.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, out.String())
+	}
+
+	// Decompressing, the input is the compressed side
+	d := newProgress(&out, "app.log.compressed", 16<<20, true)
+	if status := d.status(8<<20, 24<<20); !strings.Contains(status, "ratio 33.3%") {
+		t.Errorf("Expected the ratio of the compressed input, got %q", status)
+	}
+}
+
+func TestCmdCompress_Progress(t *testing.T) {
+	data := bytes.Repeat([]byte("progress of a long compress job\n"), 200)
+	filePath := writeTempFile(t, "job.log", data)
+
+	streams, expected, _ := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{"-c", filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	streams, out, errOut := testStreams(nil)
+	if err := cli.ExecuteCommand("compress", []string{"-progress", "-c", filePath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	finalStatus := regexp.MustCompile(`\r[^\r]*: 0\.0 of 0\.0 MB \(100%\), ratio [0-9.]+%, done in [^\r]+\n$`)
+	if !finalStatus.MatchString(errOut.String()) || strings.Count(errOut.String(), "(100%)") != 1 {
+		t.Errorf("Expected a single final status line, got %q", errOut.String())
+	}
+	if !bytes.Equal(out.Bytes(), expected.Bytes()) {
+		t.Error("Expected the same output as without -progress")
+	}
+
+	// decompress advances frame by frame through concatenated files
+	compressedPath := writeTempFile(t, "job.log.compressed", append(out.Bytes(), expected.Bytes()...))
+	streams, out, errOut = testStreams(nil)
+	if err := cli.ExecuteCommand("decompress", []string{"-progress", "-c", compressedPath}, streams); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), append(data, data...)) {
+		t.Errorf("Expected %d bytes back, got %d", 2*len(data), out.Len())
+	}
+	if !finalStatus.MatchString(errOut.String()) || strings.Count(errOut.String(), "(100%)") != 1 || !strings.Contains(errOut.String(), "job.log.compressed: ") {
+		t.Errorf("Expected a single final status line, got %q", errOut.String())
+	}
+}
+
+func TestCmd_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testCases := []struct {
+		name     string
+		cmd      string
+		fileName string
+		input    []byte
+	}{
+		{name: "Compress", cmd: "compress", fileName: "input.txt", input: []byte("interrupted before the output is written")},
+		{name: "Decompress", cmd: "decompress", fileName: "input.txt.compressed", input: compressed(t, []byte("interrupted"))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			filePath := writeTempFile(t, tc.fileName, tc.input)
+			streams, _, _ := testStreams(nil)
+			streams.Context = ctx
+			err := cli.ExecuteCommand(tc.cmd, []string{filePath}, streams)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Expected %v, got %v", context.Canceled, err)
+			}
+
+			// Nothing but the input is left behind
+			entries, _ := os.ReadDir(filepath.Dir(filePath))
+			if len(entries) != 1 || entries[0].Name() != filepath.Base(filePath) {
+				t.Errorf("Expected only the input to remain, got %v", entries)
+			}
+		})
+	}
+}
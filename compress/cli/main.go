@@ -2,7 +2,8 @@ package cli
 
 import (
 	"fmt"
-	"io"
+	"sort"
+	"strings"
 )
 
 var commands = make(map[string]Command)
@@ -12,11 +13,22 @@ func Register(name string, cmd Command) {
 	commands[name] = cmd
 }
 
-// ExecuteCommand executes a command by its name.
-func ExecuteCommand(name string, out io.Writer) error {
-	cmd, ok := commands[name]
+// Names returns the names of the registered commands, sorted.
+func Names() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExecuteCommand executes a command by its name. Leading dashes are ignored, so
+// "-compress" and "compress" run the same command.
+func ExecuteCommand(name string, args []string, streams Streams) error {
+	cmd, ok := commands[strings.TrimLeft(name, "-")]
 	if !ok {
 		return fmt.Errorf("command %s not found", name)
 	}
-	return cmd.Execute(out)
+	return cmd.Execute(args, streams)
 }
@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+type recordingCommand struct {
+	args []string
+}
+
+func (c *recordingCommand) Execute(args []string, streams Streams) error {
+	c.args = args
+	_, err := streams.Out.Write([]byte("ran"))
+	return err
+}
+
+func TestExecuteCommand(t *testing.T) {
+	cmd := &recordingCommand{}
+	Register("record", cmd)
+	defer delete(commands, "record")
+
+	for _, name := range []string{"record", "-record", "--record"} {
+		var out bytes.Buffer
+		err := ExecuteCommand(name, []string{"-x", "file"}, Streams{Out: &out})
+		if err != nil {
+			t.Fatalf("Expected no error for %q, got %v", name, err)
+		}
+		if out.String() != "ran" || !reflect.DeepEqual(cmd.args, []string{"-x", "file"}) {
+			t.Errorf("Unexpected execution for %q: output %q, args %v", name, out.String(), cmd.args)
+		}
+	}
+
+	if err := ExecuteCommand("missing", nil, Streams{}); err == nil || err.Error() != "command missing not found" {
+		t.Errorf("Expected command not found error, got %v", err)
+	}
+}
+
+func TestNames(t *testing.T) {
+	Register("b", &recordingCommand{})
+	Register("a", &recordingCommand{})
+	defer delete(commands, "a")
+	defer delete(commands, "b")
+
+	if names := Names(); !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("Expected sorted names, got %v", names)
+	}
+}
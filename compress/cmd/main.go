@@ -1,8 +1,13 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
 	"github.com/Farber98/cc-solutions/compress/cli"
 	"github.com/Farber98/cc-solutions/compress/cli/commands"
@@ -10,20 +15,42 @@ import (
 
 func main() {
 	// Register commands
-	cli.Register("-count", &commands.CmdCount{})
-	cli.Register("-compress", &commands.CmdCompress{})
-	cli.Register("-decompress", &commands.CmdDecompress{})
+	cli.Register("count", &commands.CmdStats{})
+	cli.Register("stats", &commands.CmdStats{})
+	cli.Register("compress", &commands.CmdCompress{})
+	cli.Register("decompress", &commands.CmdDecompress{})
+	cli.Register("undelta", &commands.CmdDecompress{})
+	cli.Register("test", &commands.CmdTest{})
+	cli.Register("read", &commands.CmdRead{})
+	cli.Register("tree", &commands.CmdTree{})
+	cli.Register("train", &commands.CmdTrain{})
+	cli.Register("bench", &commands.CmdBench{})
+	cli.Register("archive", &commands.CmdArchive{})
+	cli.Register("list", &commands.CmdList{})
+	cli.Register("extract", &commands.CmdExtract{})
+	cli.Register("cat", &commands.CmdCat{})
 
 	// Check args have been provided
 	if len(os.Args) < 2 {
-		log.Fatal("Usage: go run main.go [command]")
+		fmt.Fprintf(os.Stderr, "Usage: go run main.go [command] [flags] [args]\n\nCommands: %s\n", strings.Join(cli.Names(), ", "))
+		os.Exit(2)
 	}
 
 	// Get the command name from the command line arguments
 	commandName := os.Args[1]
 
+	// Stop cleanly on the first interrupt, a second one kills the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	// Execute the command
-	if err := cli.ExecuteCommand(commandName, os.Stdout); err != nil {
-		log.Fatal("Error:", err)
+	streams := cli.Streams{In: os.Stdin, Out: os.Stdout, Err: os.Stderr, Context: ctx}
+	err := cli.ExecuteCommand(commandName, os.Args[2:], streams)
+	stop()
+	if err != nil {
+		log.Fatal("Error: ", err)
 	}
 }
@@ -0,0 +1,123 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"math"
+
+	"github.com/Farber98/cc-solutions/compress/bitio"
+	"github.com/Farber98/cc-solutions/compress/huffman"
+)
+
+// AdaptiveCompressor implements the Compressor interface with FGK adaptive Huffman coding.
+// The code table is built while encoding, so the codes argument is ignored and may be nil.
+// Use AdaptiveWriter to code a stream without holding it in memory.
+type AdaptiveCompressor struct{}
+
+// Encode encodes the source text with a fresh adaptive tree and terminates it with an end of stream symbol.
+func (c *AdaptiveCompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	var buffer bytes.Buffer
+	w := NewAdaptiveWriter(&buffer)
+
+	// Writes to a bytes.Buffer never fail
+	w.Write(sourceText)
+	w.Close()
+
+	return buffer.Bytes()
+}
+
+// AdaptiveDecompressor implements the Decompressor interface with FGK adaptive Huffman coding.
+// The code table is rebuilt while decoding, so the codeTable argument is ignored and may be nil.
+type AdaptiveDecompressor struct {
+	lengthLimit
+}
+
+// Decode decodes the encoded text up to the end of stream symbol, or fails once it goes past the
+// limit set with SetMaxLength.
+func (d *AdaptiveDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	var r io.Reader = NewAdaptiveReader(bytes.NewReader(encodedText))
+	if d.limited && d.maxLength < math.MaxInt64 {
+		// One byte past the limit is enough to tell
+		r = io.LimitReader(r, int64(d.maxLength)+1)
+	}
+	decodedText, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.checkLength(uint64(len(decodedText))); err != nil {
+		return nil, err
+	}
+	return decodedText, nil
+}
+
+// AdaptiveWriter compresses everything written to it in a single pass.
+type AdaptiveWriter struct {
+	tree *huffman.AdaptiveTree
+	bits *bitio.Writer
+}
+
+// NewAdaptiveWriter creates an adaptive Huffman writer on top of w. Close must be called to terminate the stream.
+func NewAdaptiveWriter(w io.Writer) *AdaptiveWriter {
+	return &AdaptiveWriter{
+		tree: huffman.NewAdaptiveTree(),
+		bits: bitio.NewWriter(w),
+	}
+}
+
+// Write encodes p.
+func (aw *AdaptiveWriter) Write(p []byte) (int, error) {
+	for i, char := range p {
+		if err := aw.tree.Encode(int(char), aw.bits); err != nil {
+			return i, err
+		}
+	}
+	return len(p), nil
+}
+
+// Close writes the end of stream symbol and flushes the remaining bits. It does not close the underlying writer.
+func (aw *AdaptiveWriter) Close() error {
+	if err := aw.tree.Encode(huffman.AdaptiveEOF, aw.bits); err != nil {
+		return err
+	}
+	return aw.bits.Flush()
+}
+
+// AdaptiveReader decompresses an adaptive Huffman stream.
+type AdaptiveReader struct {
+	tree *huffman.AdaptiveTree
+	bits *bitio.Reader
+	done bool
+}
+
+// NewAdaptiveReader creates an adaptive Huffman reader on top of r.
+func NewAdaptiveReader(r io.Reader) *AdaptiveReader {
+	return &AdaptiveReader{
+		tree: huffman.NewAdaptiveTree(),
+		bits: bitio.NewReader(r),
+	}
+}
+
+// Read decodes up to len(p) bytes. It returns io.EOF after the end of stream symbol.
+func (ar *AdaptiveReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) && !ar.done {
+		symbol, err := ar.tree.Decode(ar.bits)
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+		if symbol == huffman.AdaptiveEOF {
+			ar.done = true
+			break
+		}
+		p[n] = byte(symbol)
+		n++
+	}
+
+	if ar.done && n == 0 {
+		return 0, io.EOF
+	}
+	return n, nil
+}
@@ -0,0 +1,90 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+)
+
+func TestAdaptiveRoundTrip(t *testing.T) {
+	random := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	testCases := []struct {
+		name   string
+		source []byte
+	}{
+		{name: "Empty", source: []byte{}},
+		{name: "SingleByte", source: []byte("a")},
+		{name: "SingleSymbol", source: bytes.Repeat([]byte("z"), 100)},
+		{name: "Text", source: []byte("aabbccddddccbbaaaabbccddddccbbaa")},
+		{name: "AllBytes", source: allBytes()},
+		{name: "Random", source: random},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := (&AdaptiveCompressor{}).Encode(tc.source, nil)
+			decoded, err := (&AdaptiveDecompressor{}).Decode(encoded, nil)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !bytes.Equal(decoded, tc.source) {
+				t.Errorf("Decode() failed, expected: %v, got: %v", tc.source, decoded)
+			}
+		})
+	}
+}
+
+func TestAdaptiveSmallInputHasNoTableOverhead(t *testing.T) {
+	source := []byte("key=value\n")
+	encoded := (&AdaptiveCompressor{}).Encode(source, nil)
+
+	// Every first occurrence costs its 9 bit escape plus the NYT path, never a table entry
+	if len(encoded) > 2*len(source) {
+		t.Errorf("Expected at most %d bytes, got %d", 2*len(source), len(encoded))
+	}
+}
+
+func TestAdaptiveStreaming(t *testing.T) {
+	source := bytes.Repeat([]byte("streaming adaptive huffman "), 200)
+
+	// Compress through a pipe, so neither side can see the whole input up front
+	pr, pw := io.Pipe()
+	go func() {
+		w := NewAdaptiveWriter(pw)
+		for i := 0; i < len(source); i += 7 {
+			end := i + 7
+			if end > len(source) {
+				end = len(source)
+			}
+			w.Write(source[i:end])
+		}
+		pw.CloseWithError(w.Close())
+	}()
+
+	decoded, err := io.ReadAll(NewAdaptiveReader(pr))
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	if !bytes.Equal(decoded, source) {
+		t.Error("Streamed round trip does not match the source")
+	}
+}
+
+func TestAdaptiveTruncated(t *testing.T) {
+	encoded := (&AdaptiveCompressor{}).Encode([]byte("truncated stream"), nil)
+	_, err := (&AdaptiveDecompressor{}).Decode(encoded[:len(encoded)-2], nil)
+	if err == nil {
+		t.Error("Expected an error for a truncated stream, got nil")
+	}
+}
+
+func allBytes() []byte {
+	data := make([]byte, 256)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return data
+}
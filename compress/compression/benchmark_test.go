@@ -0,0 +1,89 @@
+package compress
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// corpus returns the bundled sample files used to compare coders.
+func corpus(b *testing.B) map[string][]byte {
+	paths, err := filepath.Glob("../tests/corpus/*")
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	files := make(map[string][]byte)
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		files[filepath.Base(path)] = contents
+	}
+	return files
+}
+
+func BenchmarkCompressCorpus(b *testing.B) {
+	coders := []struct {
+		name   string
+		encode func(b *testing.B, contents []byte) int
+	}{
+		{name: "huffman", encode: func(b *testing.B, contents []byte) int {
+			return len((&HuffmanCompressor{}).Encode(contents, nil))
+		}},
+		{name: "adaptive", encode: func(b *testing.B, contents []byte) int {
+			return len((&AdaptiveCompressor{}).Encode(contents, nil))
+		}},
+		{name: "range", encode: func(b *testing.B, contents []byte) int {
+			return len((&RangeCompressor{}).Encode(contents, nil))
+		}},
+		{name: "range-context", encode: func(b *testing.B, contents []byte) int {
+			return len((&RangeCompressor{Context: true}).Encode(contents, nil))
+		}},
+		{name: "word", encode: func(b *testing.B, contents []byte) int {
+			return len((&WordCompressor{}).Encode(contents, nil))
+		}},
+	}
+
+	for name, contents := range corpus(b) {
+		for _, coder := range coders {
+			b.Run(name+"/"+coder.name, func(b *testing.B) {
+				b.SetBytes(int64(len(contents)))
+				size := 0
+				for i := 0; i < b.N; i++ {
+					size = coder.encode(b, contents)
+				}
+				b.ReportMetric(float64(size)/float64(len(contents)), "ratio")
+			})
+		}
+	}
+}
+
+func BenchmarkDecompressCorpus(b *testing.B) {
+	coders := []struct {
+		name         string
+		compressor   Compressor
+		decompressor Decompressor
+	}{
+		{name: "huffman", compressor: &HuffmanCompressor{}, decompressor: &HuffmanDecompressor{}},
+		{name: "adaptive", compressor: &AdaptiveCompressor{}, decompressor: &AdaptiveDecompressor{}},
+		{name: "range", compressor: &RangeCompressor{}, decompressor: &RangeDecompressor{}},
+		{name: "range-context", compressor: &RangeCompressor{Context: true}, decompressor: &RangeDecompressor{}},
+		{name: "word", compressor: &WordCompressor{}, decompressor: &WordDecompressor{}},
+	}
+
+	for name, contents := range corpus(b) {
+		for _, coder := range coders {
+			encoded := coder.compressor.Encode(contents, nil)
+			b.Run(name+"/"+coder.name, func(b *testing.B) {
+				b.SetBytes(int64(len(contents)))
+				for i := 0; i < b.N; i++ {
+					if _, err := coder.decompressor.Decode(encoded, nil); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package compress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+
+	"github.com/Farber98/cc-solutions/compress/container"
+)
+
+// SyncMarker starts every block of a BlocksCompressor payload, so a decoder can find the next
+// block after damaged data.
+var SyncMarker = []byte{0xFF, 'H', 'Z', 'S', 'Y', 'N', 'C', 0x00}
+
+// DefaultBlockSize is the number of uncompressed bytes in a block.
+const DefaultBlockSize = 64 << 10
+
+// ByteRange is the range [Start, End) of the uncompressed data.
+type ByteRange struct {
+	Start uint64
+	End   uint64
+}
+
+// BlocksCompressor implements the Compressor interface with static Huffman coding in self
+// contained blocks, each with its own code table, so damage to one block does not spread to the
+// others. Every block is the sync marker, the uvarint offset of the block in the uncompressed
+// data, the uvarint size of its data, the big endian CRC32 of the uncompressed block, the big
+// endian CRC32 of the fields after the marker, and the data, a HuffmanCompressor payload.
+// The codes argument is ignored and may be nil.
+type BlocksCompressor struct {
+	// BlockSize is the number of uncompressed bytes in a block, DefaultBlockSize when zero.
+	BlockSize int
+}
+
+// Encode encodes the source text block by block.
+func (c *BlocksCompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	size := c.BlockSize
+	if size <= 0 {
+		size = DefaultBlockSize
+	}
+
+	var buf []byte
+	huffman := &HuffmanCompressor{}
+	for start := 0; start < len(sourceText); start += size {
+		end := start + size
+		if end > len(sourceText) {
+			end = len(sourceText)
+		}
+		data := huffman.Encode(sourceText[start:end], nil)
+
+		buf = append(buf, SyncMarker...)
+		fields := len(buf)
+		buf = binary.AppendUvarint(buf, uint64(start))
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		buf = binary.BigEndian.AppendUint32(buf, container.Checksum(sourceText[start:end]))
+		buf = binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf[fields:]))
+		buf = append(buf, data...)
+	}
+	return buf
+}
+
+// BlocksDecompressor implements the Decompressor interface for payloads of BlocksCompressor.
+// The codeTable argument is ignored and may be nil.
+type BlocksDecompressor struct {
+	lengthLimit
+}
+
+// Decode decodes every block in order and fails on the first damaged one. Use Recover to
+// decode around damage.
+func (d *BlocksDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	var decodedText []byte
+	for pos := 0; pos < len(encodedText); {
+		offset, block, next, err := readBlock(encodedText, pos)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding block at byte %d: %w", pos, err)
+		}
+		if offset != uint64(len(decodedText)) {
+			return nil, fmt.Errorf("error decoding block at byte %d: expected offset %d, got %d", pos, len(decodedText), offset)
+		}
+		if err := d.checkLength(uint64(len(decodedText) + len(block))); err != nil {
+			return nil, fmt.Errorf("error decoding block at byte %d: %w", pos, err)
+		}
+		decodedText = append(decodedText, block...)
+		pos = next
+	}
+	if decodedText == nil {
+		decodedText = []byte{}
+	}
+	return decodedText, nil
+}
+
+// Recover decodes every intact block of a BlocksCompressor payload, skipping to the next sync
+// marker after damage. length is the expected size of the uncompressed data; it is ignored when
+// the payload could not possibly hold that much. Bytes of damaged blocks are left zero, so
+// intact blocks keep their offsets, and are reported as damaged ranges in ascending order.
+func Recover(encodedText []byte, length uint64) ([]byte, []ByteRange) {
+	// Every symbol takes at least one bit, a larger length comes from a damaged header
+	if length > uint64(len(encodedText))*8 {
+		length = 0
+	}
+
+	var decodedText []byte
+	var intact []ByteRange
+	for pos := 0; ; {
+		i := bytes.Index(encodedText[pos:], SyncMarker)
+		if i < 0 {
+			break
+		}
+		start := pos + i
+
+		offset, block, next, err := readBlock(encodedText, start)
+		end := offset + uint64(len(block))
+		if err != nil || end > uint64(len(encodedText))*8 || overlaps(intact, offset, end) {
+			// Not a block, or a damaged one: look for the next marker
+			pos = start + 1
+			continue
+		}
+
+		if end > uint64(len(decodedText)) {
+			decodedText = append(decodedText, make([]byte, end-uint64(len(decodedText)))...)
+		}
+		copy(decodedText[offset:], block)
+		if len(block) > 0 {
+			intact = append(intact, ByteRange{Start: offset, End: end})
+		}
+		pos = next
+	}
+
+	if length > uint64(len(decodedText)) {
+		decodedText = append(decodedText, make([]byte, length-uint64(len(decodedText)))...)
+	}
+	if decodedText == nil {
+		decodedText = []byte{}
+	}
+	return decodedText, gaps(intact, uint64(len(decodedText)))
+}
+
+// readBlock reads and verifies the block whose sync marker is at pos. It returns the offset of
+// the block, its decoded bytes and the position after it.
+func readBlock(encodedText []byte, pos int) (uint64, []byte, int, error) {
+	if !bytes.HasPrefix(encodedText[pos:], SyncMarker) {
+		return 0, nil, 0, fmt.Errorf("missing sync marker")
+	}
+
+	fields := pos + len(SyncMarker)
+	reader := bytes.NewReader(encodedText[fields:])
+	offset, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("error reading block offset: %w", err)
+	}
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return 0, nil, 0, fmt.Errorf("error reading block size: %w", err)
+	}
+	checksums := make([]byte, 8)
+	if _, err := io.ReadFull(reader, checksums); err != nil {
+		return 0, nil, 0, fmt.Errorf("error reading block checksums: %w", err)
+	}
+	dataStart := len(encodedText) - reader.Len()
+
+	if sum := crc32.ChecksumIEEE(encodedText[fields : dataStart-4]); sum != binary.BigEndian.Uint32(checksums[4:]) {
+		return 0, nil, 0, fmt.Errorf("%w: block header checksum mismatch", container.ErrChecksum)
+	}
+	if size > uint64(len(encodedText)-dataStart) {
+		return 0, nil, 0, fmt.Errorf("error reading block data: unexpected end of data")
+	}
+	dataEnd := dataStart + int(size)
+
+	block, err := (&HuffmanDecompressor{}).Decode(encodedText[dataStart:dataEnd], nil)
+	if err != nil {
+		return 0, nil, 0, err
+	}
+	if sum := container.Checksum(block); sum != binary.BigEndian.Uint32(checksums[:4]) {
+		return 0, nil, 0, fmt.Errorf("%w: block checksum mismatch", container.ErrChecksum)
+	}
+	return offset, block, dataEnd, nil
+}
+
+// overlaps reports whether [start, end) overlaps one of the ranges.
+func overlaps(ranges []ByteRange, start, end uint64) bool {
+	for _, r := range ranges {
+		if start < r.End && r.Start < end {
+			return true
+		}
+	}
+	return false
+}
+
+// gaps returns the parts of [0, length) not covered by the ranges.
+func gaps(ranges []ByteRange, length uint64) []ByteRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+
+	var missing []ByteRange
+	next := uint64(0)
+	for _, r := range ranges {
+		if r.Start > next {
+			missing = append(missing, ByteRange{Start: next, End: r.Start})
+		}
+		next = r.End
+	}
+	if next < length {
+		missing = append(missing, ByteRange{Start: next, End: length})
+	}
+	return missing
+}
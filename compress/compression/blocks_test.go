@@ -0,0 +1,161 @@
+package compress
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/container"
+)
+
+func TestBlocksRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name      string
+		source    []byte
+		blockSize int
+	}{
+		{name: "Empty", source: []byte{}},
+		{name: "SingleSymbol", source: bytes.Repeat([]byte("z"), 100), blockSize: 7},
+		{name: "AllBytes", source: allBytes(), blockSize: 16},
+		{name: "Logs", source: logLines(500), blockSize: 1000},
+		{name: "DefaultBlockSize", source: logLines(5000)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := (&BlocksCompressor{BlockSize: tc.blockSize}).Encode(tc.source, nil)
+			decoded, err := (&BlocksDecompressor{}).Decode(encoded, nil)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !bytes.Equal(decoded, tc.source) {
+				t.Errorf("Decode() failed, expected %d bytes, got %d", len(tc.source), len(decoded))
+			}
+
+			recovered, damaged := Recover(encoded, uint64(len(tc.source)))
+			if !bytes.Equal(recovered, tc.source) || len(damaged) != 0 {
+				t.Errorf("Recover() of intact data failed, got %d bytes and damage %v", len(recovered), damaged)
+			}
+		})
+	}
+}
+
+func TestRecover(t *testing.T) {
+	source := logLines(2000)
+	const blockSize = 10000
+	encoded := (&BlocksCompressor{BlockSize: blockSize}).Encode(source, nil)
+	length := uint64(len(source))
+
+	// blockStart returns the position of the sync marker of block i.
+	blockStart := func(i int) int {
+		pos := -1
+		for n := 0; n <= i; n++ {
+			pos += 1 + bytes.Index(encoded[pos+1:], SyncMarker)
+		}
+		return pos
+	}
+
+	testCases := []struct {
+		name     string
+		damage   func(data []byte) []byte
+		expected []ByteRange
+	}{
+		{
+			name: "Flipped_bit_in_data",
+			damage: func(data []byte) []byte {
+				data[blockStart(2)+len(SyncMarker)+40] ^= 0x10
+				return data
+			},
+			expected: []ByteRange{{Start: 2 * blockSize, End: 3 * blockSize}},
+		},
+		{
+			name: "Damaged_marker",
+			damage: func(data []byte) []byte {
+				data[blockStart(1)+3] ^= 0x01
+				return data
+			},
+			expected: []ByteRange{{Start: blockSize, End: 2 * blockSize}},
+		},
+		{
+			name: "Damaged_block_header",
+			damage: func(data []byte) []byte {
+				data[blockStart(4)+len(SyncMarker)] ^= 0x80
+				return data
+			},
+			expected: []ByteRange{{Start: 4 * blockSize, End: 5 * blockSize}},
+		},
+		{
+			name: "Two_damaged_blocks",
+			damage: func(data []byte) []byte {
+				data[blockStart(0)+100] ^= 0xFF
+				data[blockStart(3)+100] ^= 0xFF
+				return data
+			},
+			expected: []ByteRange{{Start: 0, End: blockSize}, {Start: 3 * blockSize, End: 4 * blockSize}},
+		},
+		{
+			name: "Truncated",
+			damage: func(data []byte) []byte {
+				return data[:blockStart(5)+50]
+			},
+			expected: []ByteRange{{Start: 5 * blockSize, End: length}},
+		},
+		{
+			name: "Garbage_inserted",
+			damage: func(data []byte) []byte {
+				at := blockStart(2) + 30
+				garbage := append([]byte("garbage"), SyncMarker...)
+				return append(append(append([]byte{}, data[:at]...), garbage...), data[at:]...)
+			},
+			expected: []ByteRange{{Start: 2 * blockSize, End: 3 * blockSize}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			damaged := tc.damage(append([]byte{}, encoded...))
+			if _, err := (&BlocksDecompressor{}).Decode(damaged, nil); err == nil {
+				t.Errorf("Expected Decode() to fail on damaged data")
+			}
+
+			recovered, ranges := Recover(damaged, length)
+			if !reflect.DeepEqual(ranges, tc.expected) {
+				t.Fatalf("Expected damaged ranges %v, got %v", tc.expected, ranges)
+			}
+			if uint64(len(recovered)) != length {
+				t.Fatalf("Expected %d recovered bytes, got %d", length, len(recovered))
+			}
+
+			// Everything outside the damaged ranges is exactly the source
+			next := uint64(0)
+			for _, r := range append(ranges, ByteRange{Start: length, End: length}) {
+				if !bytes.Equal(recovered[next:r.Start], source[next:r.Start]) {
+					t.Errorf("Expected bytes %d-%d to be recovered", next, r.Start)
+				}
+				next = r.End
+			}
+		})
+	}
+}
+
+func TestRecover_DamagedLength(t *testing.T) {
+	source := logLines(100)
+	encoded := (&BlocksCompressor{BlockSize: 1000}).Encode(source, nil)
+
+	// A length the payload cannot hold is ignored instead of allocated
+	recovered, damaged := Recover(encoded, 1<<60)
+	if !bytes.Equal(recovered, source) || len(damaged) != 0 {
+		t.Errorf("Expected the source back, got %d bytes and damage %v", len(recovered), damaged)
+	}
+}
+
+func TestReadBlock_Checksum(t *testing.T) {
+	encoded := (&BlocksCompressor{}).Encode([]byte("checksummed block"), nil)
+	// The first byte of the data checksum, which the header checksum covers
+	encoded[len(SyncMarker)+2] ^= 0x01
+
+	if _, _, _, err := readBlock(encoded, 0); !errors.Is(err, container.ErrChecksum) {
+		t.Errorf("Expected a checksum error, got %v", err)
+	}
+}
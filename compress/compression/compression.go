@@ -40,11 +40,9 @@ func (c *DefaultCompressor) Encode(sourceText []byte, codes map[byte]string) []b
 		}
 	}
 
-	// Handle remaining bits
+	// Handle remaining bits, they are already in place and the rest of the byte is zero padding
 	if byteIndex != 0 {
-		// Calculate the number of bits to shift left to fill the byte
-		shift := 8 - byteIndex
-		byteBuffer = append(byteBuffer, byteValue<<shift)
+		byteBuffer = append(byteBuffer, byteValue)
 	}
 
 	return byteBuffer
@@ -3,6 +3,7 @@ package compress
 import (
 	"bytes"
 	"testing"
+	"testing/quick"
 )
 
 func TestEncode(t *testing.T) {
@@ -26,3 +27,52 @@ func TestEncode(t *testing.T) {
 		t.Errorf("Expected encoded bytes %v, got %v", expectedBytes, encodedBytes)
 	}
 }
+
+func TestEncodePartialByte(t *testing.T) {
+	codes := map[byte]string{'a': "0", 'b': "10", 'c': "11"}
+
+	// 0 10 11 0 10 11 -> 01011010 11, the last byte is padded with zeros
+	encodedBytes := (&DefaultCompressor{}).Encode([]byte("abcabc"), codes)
+
+	expectedBytes := []byte{0b01011010, 0b11000000}
+	if !bytes.Equal(encodedBytes, expectedBytes) {
+		t.Errorf("Expected encoded bytes %08b, got %08b", expectedBytes, encodedBytes)
+	}
+}
+
+func TestRoundTripProperty(t *testing.T) {
+	coders := []struct {
+		name         string
+		compressor   Compressor
+		decompressor Decompressor
+	}{
+		{name: "Huffman", compressor: &HuffmanCompressor{}, decompressor: &HuffmanDecompressor{}},
+		{name: "Adaptive", compressor: &AdaptiveCompressor{}, decompressor: &AdaptiveDecompressor{}},
+		{name: "Range", compressor: &RangeCompressor{}, decompressor: &RangeDecompressor{}},
+		{name: "RangeContext", compressor: &RangeCompressor{Context: true}, decompressor: &RangeDecompressor{}},
+		{name: "Word", compressor: &WordCompressor{}, decompressor: &WordDecompressor{}},
+		{name: "Seekable", compressor: &SeekableCompressor{Interval: 10}, decompressor: &SeekableDecompressor{}},
+		{name: "Blocks", compressor: &BlocksCompressor{BlockSize: 10}, decompressor: &BlocksDecompressor{}},
+	}
+
+	for _, coder := range coders {
+		t.Run(coder.name, func(t *testing.T) {
+			// Property: any input round trips, whatever its size and alphabet. The alphabet argument
+			// folds the random bytes onto 1, 86, 171 or 256 symbols, so single symbol inputs come up often.
+			property := func(data []byte, alphabet uint8) bool {
+				source := make([]byte, len(data))
+				for i, b := range data {
+					source[i] = byte(int(b) % (int(alphabet)%4*85 + 1))
+				}
+
+				encoded := coder.compressor.Encode(source, nil)
+				decoded, err := coder.decompressor.Decode(encoded, nil)
+				return err == nil && bytes.Equal(decoded, source)
+			}
+
+			if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
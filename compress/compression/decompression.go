@@ -7,6 +7,47 @@ type Decompressor interface {
 	Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error)
 }
 
+// LimitedDecompressor is a Decompressor that can be bounded in what it decodes. A few bytes of
+// some payloads stand for gigabytes, so frames set the most their data can take, and Decode
+// fails before it allocates more. Every decompressor of a frame method implements it.
+type LimitedDecompressor interface {
+	Decompressor
+	// SetMaxLength makes Decode fail on payloads that decode to more than n bytes.
+	SetMaxLength(n uint64)
+}
+
+var (
+	_ LimitedDecompressor = (*HuffmanDecompressor)(nil)
+	_ LimitedDecompressor = (*AdaptiveDecompressor)(nil)
+	_ LimitedDecompressor = (*RangeDecompressor)(nil)
+	_ LimitedDecompressor = (*WordDecompressor)(nil)
+	_ LimitedDecompressor = (*SeekableDecompressor)(nil)
+	_ LimitedDecompressor = (*BlocksDecompressor)(nil)
+	_ LimitedDecompressor = (*RLEDecompressor)(nil)
+	_ LimitedDecompressor = (*DictionaryDecompressor)(nil)
+	_ LimitedDecompressor = (*DeltaDecompressor)(nil)
+)
+
+// lengthLimit implements SetMaxLength for the decompressors that embed it. There is no limit
+// until one is set.
+type lengthLimit struct {
+	maxLength uint64
+	limited   bool
+}
+
+// SetMaxLength makes Decode fail on payloads that decode to more than n bytes.
+func (l *lengthLimit) SetMaxLength(n uint64) {
+	l.maxLength, l.limited = n, true
+}
+
+// checkLength fails when length bytes are more than the limit.
+func (l *lengthLimit) checkLength(length uint64) error {
+	if l.limited && length > l.maxLength {
+		return fmt.Errorf("invalid length: %d bytes, more than the %d allowed", length, l.maxLength)
+	}
+	return nil
+}
+
 // DefaultCompressor implements the Compressor interface with the default compression operations.
 type DefaultDecompressor struct{}
 
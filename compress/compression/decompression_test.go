@@ -2,6 +2,7 @@ package compress
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -37,3 +38,40 @@ func TestDecode(t *testing.T) {
 		})
 	}
 }
+
+func TestSetMaxLength(t *testing.T) {
+	source := []byte(strings.Repeat("a long token repeated over and over ", 200) + strings.Repeat("z", 5000))
+
+	testCases := []struct {
+		name         string
+		compressor   Compressor
+		decompressor func() LimitedDecompressor
+	}{
+		{name: "Huffman", compressor: &HuffmanCompressor{}, decompressor: func() LimitedDecompressor { return &HuffmanDecompressor{} }},
+		{name: "Adaptive", compressor: &AdaptiveCompressor{}, decompressor: func() LimitedDecompressor { return &AdaptiveDecompressor{} }},
+		{name: "Range", compressor: &RangeCompressor{}, decompressor: func() LimitedDecompressor { return &RangeDecompressor{} }},
+		{name: "Word", compressor: &WordCompressor{}, decompressor: func() LimitedDecompressor { return &WordDecompressor{} }},
+		{name: "Seekable", compressor: &SeekableCompressor{}, decompressor: func() LimitedDecompressor { return &SeekableDecompressor{} }},
+		{name: "Blocks", compressor: &BlocksCompressor{}, decompressor: func() LimitedDecompressor { return &BlocksDecompressor{} }},
+		{name: "RLE", compressor: &RLECompressor{}, decompressor: func() LimitedDecompressor { return &RLEDecompressor{} }},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := tc.compressor.Encode(source, nil)
+
+			d := tc.decompressor()
+			d.SetMaxLength(uint64(len(source)))
+			if _, err := d.Decode(encoded, nil); err != nil {
+				t.Fatalf("Expected no error at the exact length, got %v", err)
+			}
+
+			d = tc.decompressor()
+			d.SetMaxLength(uint64(len(source)) - 1)
+			_, err := d.Decode(encoded, nil)
+			if err == nil || !strings.Contains(err.Error(), "invalid length") {
+				t.Errorf("Expected an error for a payload over the limit, got %v", err)
+			}
+		})
+	}
+}
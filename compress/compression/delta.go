@@ -0,0 +1,227 @@
+package compress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/Farber98/cc-solutions/compress/container"
+)
+
+// Constants for matching against the reference.
+const (
+	// deltaWindow is the number of bytes hashed to find match candidates in the reference.
+	deltaWindow = 8
+	// deltaMinMatch is the shortest copy worth an instruction instead of literals.
+	deltaMinMatch = 16
+	// deltaMaxHashBits bounds the hash table to 4M entries, 16 MB.
+	deltaMaxHashBits = 22
+)
+
+// ErrReference is returned when a delta payload was made against a different reference.
+var ErrReference = errors.New("reference does not match")
+
+// DeltaCompressor implements the Compressor interface by encoding the source text as copies of
+// ranges of a reference plus literals, like VCDIFF. The payload is the big endian CRC32 and the
+// uvarint length of the reference, the uvarint size of the instructions, the instructions as a
+// HuffmanCompressor payload, and the literals as a HuffmanCompressor payload. The codes argument
+// is ignored and may be nil.
+//
+// Instructions are uvarints: n<<1 adds the next n literals, n<<1|1 copies n bytes of the
+// reference and is followed by the varint distance of the copy from the end of the previous one.
+type DeltaCompressor struct {
+	Reference []byte
+}
+
+// Encode encodes the source text against the reference.
+func (c *DeltaCompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	var instructions, literals []byte
+	previousEnd := 0
+	addLiterals := func(text []byte) {
+		if len(text) > 0 {
+			instructions = binary.AppendUvarint(instructions, uint64(len(text))<<1)
+			literals = append(literals, text...)
+		}
+	}
+
+	index := newReferenceIndex(c.Reference)
+	pending := 0
+	for pos := 0; pos+deltaWindow <= len(sourceText); {
+		// A substitution keeps the data after it aligned with the previous copy, try that first
+		offset, length := previousEnd+pos-pending, 0
+		if offset < len(c.Reference) {
+			length = matchLength(c.Reference[offset:], sourceText[pos:])
+		}
+		if candidate := index.lookup(sourceText[pos:]); candidate >= 0 {
+			if n := matchLength(c.Reference[candidate:], sourceText[pos:]); n > length {
+				offset, length = candidate, n
+			}
+		}
+		if length < deltaMinMatch {
+			pos++
+			continue
+		}
+
+		// Extend the match backwards over bytes that would otherwise become literals
+		for pos > pending && offset > 0 && c.Reference[offset-1] == sourceText[pos-1] {
+			pos, offset, length = pos-1, offset-1, length+1
+		}
+
+		addLiterals(sourceText[pending:pos])
+		instructions = binary.AppendUvarint(instructions, uint64(length)<<1|1)
+		instructions = binary.AppendVarint(instructions, int64(offset-previousEnd))
+		pos += length
+		pending, previousEnd = pos, offset+length
+	}
+	addLiterals(sourceText[pending:])
+
+	huffman := &HuffmanCompressor{}
+	encodedInstructions := huffman.Encode(instructions, nil)
+	buf := binary.BigEndian.AppendUint32(nil, container.Checksum(c.Reference))
+	buf = binary.AppendUvarint(buf, uint64(len(c.Reference)))
+	buf = binary.AppendUvarint(buf, uint64(len(encodedInstructions)))
+	buf = append(buf, encodedInstructions...)
+	return append(buf, huffman.Encode(literals, nil)...)
+}
+
+// DeltaDecompressor implements the Decompressor interface for payloads of DeltaCompressor.
+// The codeTable argument is ignored and may be nil.
+type DeltaDecompressor struct {
+	Reference []byte
+	lengthLimit
+}
+
+// Decode rebuilds the source text from the reference, failing with ErrReference when the
+// payload was made against another one. Copies of the reference can repeat without end, so it
+// fails before the output would go past the limit set with SetMaxLength.
+func (d *DeltaDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	reader := bytes.NewReader(encodedText)
+	checksum := make([]byte, 4)
+	if _, err := io.ReadFull(reader, checksum); err != nil {
+		return nil, fmt.Errorf("error reading reference checksum: %w", err)
+	}
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading reference length: %w", err)
+	}
+	if length != uint64(len(d.Reference)) || binary.BigEndian.Uint32(checksum) != container.Checksum(d.Reference) {
+		return nil, ErrReference
+	}
+
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading instructions size: %w", err)
+	}
+	if size > uint64(reader.Len()) {
+		return nil, fmt.Errorf("invalid instructions size: %d", size)
+	}
+	start := len(encodedText) - reader.Len()
+	huffman := &HuffmanDecompressor{}
+	instructions, err := huffman.Decode(encodedText[start:start+int(size)], nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding instructions: %w", err)
+	}
+	literals, err := huffman.Decode(encodedText[start+int(size):], nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding literals: %w", err)
+	}
+
+	return applyInstructions(d.Reference, instructions, literals, &d.lengthLimit)
+}
+
+// applyInstructions runs the copy and add instructions against the reference and the literals,
+// and fails before the output would go past limit.
+func applyInstructions(reference, instructions, literals []byte, limit *lengthLimit) ([]byte, error) {
+	decodedText := []byte{}
+	reader := bytes.NewReader(instructions)
+	previousEnd := int64(0)
+	for reader.Len() > 0 {
+		op, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("error reading instruction: %w", err)
+		}
+		n := op >> 1
+		if n == 0 {
+			return nil, fmt.Errorf("invalid instruction: empty copy or add")
+		}
+
+		if op&1 == 0 {
+			if n > uint64(len(literals)) {
+				return nil, fmt.Errorf("invalid add instruction: %d literals left, need %d", len(literals), n)
+			}
+			if err := limit.checkLength(uint64(len(decodedText)) + n); err != nil {
+				return nil, fmt.Errorf("invalid add instruction: %w", err)
+			}
+			decodedText = append(decodedText, literals[:n]...)
+			literals = literals[n:]
+			continue
+		}
+
+		distance, err := binary.ReadVarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("error reading copy offset: %w", err)
+		}
+		if n > math.MaxInt64/2 || distance > math.MaxInt64/2 || distance < -math.MaxInt64/2 {
+			return nil, fmt.Errorf("invalid copy instruction")
+		}
+		offset := previousEnd + distance
+		if offset < 0 || uint64(offset)+n > uint64(len(reference)) {
+			return nil, fmt.Errorf("invalid copy instruction: %d bytes at %d are outside the reference", n, offset)
+		}
+		if err := limit.checkLength(uint64(len(decodedText)) + n); err != nil {
+			return nil, fmt.Errorf("invalid copy instruction: %w", err)
+		}
+		decodedText = append(decodedText, reference[offset:offset+int64(n)]...)
+		previousEnd = offset + int64(n)
+	}
+
+	if len(literals) > 0 {
+		return nil, fmt.Errorf("invalid instructions: %d literals left over", len(literals))
+	}
+	return decodedText, nil
+}
+
+// referenceIndex maps hashes of deltaWindow bytes to their last position in the reference.
+type referenceIndex struct {
+	table []int32
+	bits  uint
+}
+
+// newReferenceIndex indexes every position of the reference.
+func newReferenceIndex(reference []byte) *referenceIndex {
+	bits := uint(10)
+	for bits < deltaMaxHashBits && 1<<bits < len(reference) {
+		bits++
+	}
+	index := &referenceIndex{table: make([]int32, 1<<bits), bits: bits}
+	for i := range index.table {
+		index.table[i] = -1
+	}
+	for i := 0; i+deltaWindow <= len(reference) && i <= math.MaxInt32; i++ {
+		index.table[index.hash(reference[i:])] = int32(i)
+	}
+	return index
+}
+
+// hash hashes the first deltaWindow bytes of data.
+func (x *referenceIndex) hash(data []byte) uint64 {
+	return binary.LittleEndian.Uint64(data) * 0x9E3779B97F4A7C15 >> (64 - x.bits)
+}
+
+// lookup returns a reference position that may start with the same deltaWindow bytes as data,
+// or -1.
+func (x *referenceIndex) lookup(data []byte) int {
+	return int(x.table[x.hash(data)])
+}
+
+// matchLength returns the length of the common prefix of a and b.
+func matchLength(a, b []byte) int {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,132 @@
+package compress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestDeltaRoundTrip(t *testing.T) {
+	reference := logLines(2000)
+	r := rand.New(rand.NewSource(44))
+
+	// edit returns the reference with a change in the middle.
+	edit := func(change func(middle int, data []byte) []byte) []byte {
+		return change(len(reference)/2, append([]byte{}, reference...))
+	}
+
+	testCases := []struct {
+		name      string
+		reference []byte
+		source    []byte
+		maxSize   int
+	}{
+		{name: "Empty", reference: []byte{}, source: []byte{}, maxSize: 20},
+		{name: "Empty_reference", reference: []byte{}, source: []byte("no reference at all")},
+		{name: "Empty_source", reference: reference, source: []byte{}, maxSize: 20},
+		{name: "Identical", reference: reference, source: reference, maxSize: 30},
+		{name: "Substitution", reference: reference, source: edit(func(m int, d []byte) []byte {
+			copy(d[m:], "SUBSTITUTED")
+			return d
+		}), maxSize: 80},
+		{name: "Insertion", reference: reference, source: edit(func(m int, d []byte) []byte {
+			return append(d[:m:m], append([]byte("an inserted line\n"), d[m:]...)...)
+		}), maxSize: 80},
+		{name: "Deletion", reference: reference, source: edit(func(m int, d []byte) []byte {
+			return append(d[:m], d[m+500:]...)
+		}), maxSize: 60},
+		{name: "Appended", reference: reference, source: append(append([]byte{}, reference...), logLines(10)...)},
+		{name: "Reordered", reference: reference, source: append(append([]byte{}, reference[len(reference)/2:]...), reference[:len(reference)/2]...), maxSize: 60},
+		{name: "Unrelated", reference: reference, source: func() []byte {
+			data := make([]byte, 5000)
+			r.Read(data)
+			return data
+		}()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := (&DeltaCompressor{Reference: tc.reference}).Encode(tc.source, nil)
+			decoded, err := (&DeltaDecompressor{Reference: tc.reference}).Decode(encoded, nil)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !bytes.Equal(decoded, tc.source) {
+				t.Fatalf("Decode() failed, expected %d bytes, got %d", len(tc.source), len(decoded))
+			}
+			if tc.maxSize > 0 && len(encoded) > tc.maxSize {
+				t.Errorf("Expected at most %d bytes, got %d", tc.maxSize, len(encoded))
+			}
+		})
+	}
+}
+
+func TestDeltaWrongReference(t *testing.T) {
+	reference := logLines(100)
+	encoded := (&DeltaCompressor{Reference: reference}).Encode(logLines(120), nil)
+
+	other := append([]byte{}, reference...)
+	other[10] ^= 1
+	for _, ref := range [][]byte{other, reference[:len(reference)-1], nil} {
+		if _, err := (&DeltaDecompressor{Reference: ref}).Decode(encoded, nil); !errors.Is(err, ErrReference) {
+			t.Errorf("Expected ErrReference, got %v", err)
+		}
+	}
+}
+
+func TestApplyInstructionsErrors(t *testing.T) {
+	reference := []byte("0123456789")
+
+	testCases := []struct {
+		name         string
+		instructions []byte
+		literals     []byte
+	}{
+		{name: "Empty_add", instructions: []byte{0}},
+		{name: "Add_past_literals", instructions: []byte{4 << 1}, literals: []byte("abc")},
+		{name: "Leftover_literals", instructions: []byte{1 << 1}, literals: []byte("abc")},
+		{name: "Copy_past_reference", instructions: []byte{5<<1 | 1, 8 << 1}},
+		{name: "Copy_before_reference", instructions: []byte{2<<1 | 1, 1}},
+		{name: "Truncated_copy", instructions: []byte{2<<1 | 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := applyInstructions(reference, tc.instructions, tc.literals, &lengthLimit{}); err == nil {
+				t.Errorf("Expected an error")
+			}
+		})
+	}
+}
+
+func TestDeltaMaxLength(t *testing.T) {
+	reference := logLines(100)
+	target := logLines(120)
+	encoded := (&DeltaCompressor{Reference: reference}).Encode(target, nil)
+	d := &DeltaDecompressor{Reference: reference}
+	d.SetMaxLength(uint64(len(target)))
+	if _, err := d.Decode(encoded, nil); err != nil {
+		t.Fatalf("Expected no error at the exact length, got %v", err)
+	}
+	d.SetMaxLength(uint64(len(target)) - 1)
+	if _, err := d.Decode(encoded, nil); err == nil {
+		t.Error("Expected an error for a patch over the limit")
+	}
+
+	// Every copy of the whole reference goes back to its start
+	instructions := binary.AppendUvarint(nil, 10<<1|1)
+	instructions = binary.AppendVarint(instructions, 0)
+	for i := 0; i < 2000; i++ {
+		instructions = binary.AppendUvarint(instructions, 10<<1|1)
+		instructions = binary.AppendVarint(instructions, -10)
+	}
+	limit := &lengthLimit{}
+	limit.SetMaxLength(25)
+	_, err := applyInstructions([]byte("0123456789"), instructions, nil, limit)
+	if err == nil || !strings.Contains(err.Error(), "invalid length: 30 bytes") {
+		t.Errorf("Expected an error for copies over the limit, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/Farber98/cc-solutions/compress/bitio"
+	"github.com/Farber98/cc-solutions/compress/dictionary"
+)
+
+// DictionaryCompressor implements the Compressor interface with the code table of a pre-trained
+// dictionary. The payload only references the dictionary: big endian dictionary ID, uvarint
+// length and the bits of DefaultCompressor. The codes argument is ignored and may be nil.
+type DictionaryCompressor struct {
+	Dictionary *dictionary.Dictionary
+}
+
+// Encode encodes the source text with the dictionary codes.
+func (c *DictionaryCompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	buf := binary.BigEndian.AppendUint32(nil, c.Dictionary.ID)
+	buf = binary.AppendUvarint(buf, uint64(len(sourceText)))
+	compressor := &DefaultCompressor{}
+	return append(buf, compressor.Encode(sourceText, c.Dictionary.Codes)...)
+}
+
+// DictionaryDecompressor implements the Decompressor interface for payloads of DictionaryCompressor.
+// The codeTable argument is ignored and may be nil.
+type DictionaryDecompressor struct {
+	Dictionary *dictionary.Dictionary
+	lengthLimit
+}
+
+// Decode decodes the payload, failing with dictionary.ErrMismatch when it references another dictionary.
+func (d *DictionaryDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	id, err := DictionaryID(encodedText)
+	if err != nil {
+		return nil, err
+	}
+	if id != d.Dictionary.ID {
+		return nil, fmt.Errorf("%w: data references dictionary %08x, got %08x", dictionary.ErrMismatch, id, d.Dictionary.ID)
+	}
+
+	reader := bufio.NewReader(bytes.NewReader(encodedText[4:]))
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading length: %w", err)
+	}
+	if err := d.checkLength(length); err != nil {
+		return nil, err
+	}
+	return decodeSymbols(bitio.NewReader(reader), d.Dictionary.ReverseLookup(), length)
+}
+
+// DictionaryID returns the ID of the dictionary a DictionaryCompressor payload references.
+func DictionaryID(encodedText []byte) (uint32, error) {
+	if len(encodedText) < 4 {
+		return 0, fmt.Errorf("error reading dictionary ID: %w", io.ErrUnexpectedEOF)
+	}
+	return binary.BigEndian.Uint32(encodedText), nil
+}
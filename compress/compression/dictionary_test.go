@@ -0,0 +1,59 @@
+package compress
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/dictionary"
+)
+
+func TestDictionaryRoundTrip(t *testing.T) {
+	d := dictionary.Train([][]byte{
+		[]byte(`{"id":1,"name":"alice","active":true}`),
+		[]byte(`{"id":2,"name":"bob","active":false}`),
+	})
+
+	testCases := []struct {
+		name   string
+		source []byte
+	}{
+		{name: "Empty", source: []byte{}},
+		{name: "Similar", source: []byte(`{"id":3,"name":"carol","active":true}`)},
+		{name: "Unseen_bytes", source: []byte("\x00\xff binary \x80")},
+		{name: "AllBytes", source: allBytes()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := (&DictionaryCompressor{Dictionary: d}).Encode(tc.source, nil)
+			decoded, err := (&DictionaryDecompressor{Dictionary: d}).Decode(encoded, nil)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !bytes.Equal(decoded, tc.source) {
+				t.Errorf("Decode() failed, expected: %v, got: %v", tc.source, decoded)
+			}
+		})
+	}
+}
+
+func TestDictionarySmallerThanHuffman(t *testing.T) {
+	d := dictionary.Train([][]byte{[]byte(`{"id":1,"name":"alice","active":true}`)})
+	source := []byte(`{"id":42,"name":"dave","active":false}`)
+
+	withDictionary := (&DictionaryCompressor{Dictionary: d}).Encode(source, nil)
+	withTable := (&HuffmanCompressor{}).Encode(source, nil)
+	if len(withDictionary) >= len(withTable) {
+		t.Errorf("Expected dictionary payload to be smaller than %d bytes, got %d", len(withTable), len(withDictionary))
+	}
+}
+
+func TestDictionaryMismatch(t *testing.T) {
+	encoded := (&DictionaryCompressor{Dictionary: dictionary.Train([][]byte{[]byte("one")})}).Encode([]byte("one"), nil)
+
+	_, err := (&DictionaryDecompressor{Dictionary: dictionary.Train([][]byte{[]byte("two two two")})}).Decode(encoded, nil)
+	if !errors.Is(err, dictionary.ErrMismatch) {
+		t.Errorf("Expected ErrMismatch, got %v", err)
+	}
+}
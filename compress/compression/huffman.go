@@ -0,0 +1,102 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Farber98/cc-solutions/compress/bitio"
+	"github.com/Farber98/cc-solutions/compress/frequency"
+	"github.com/Farber98/cc-solutions/compress/huffman"
+)
+
+// HuffmanCompressor implements the Compressor interface with static Huffman coding and produces a
+// self contained payload: uvarint length, binary code table and the bits of DefaultCompressor.
+type HuffmanCompressor struct{}
+
+// Encode encodes the source text. When codes is nil the table is built from the source text.
+func (c *HuffmanCompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	if codes == nil {
+		codes = BuildCodes(sourceText)
+	}
+
+	buf := binary.AppendUvarint(nil, uint64(len(sourceText)))
+	buf = huffman.AppendCodeTable(buf, codes)
+	compressor := &DefaultCompressor{}
+	return append(buf, compressor.Encode(sourceText, codes)...)
+}
+
+// BuildCodes builds the Huffman code table of the source text. Empty text gets an empty table.
+func BuildCodes(sourceText []byte) map[byte]string {
+	codes := make(map[byte]string)
+	calculator := &frequency.DefaultCalculator{}
+	h := &huffman.DefaultHuffmanCoding{}
+	h.AssignCodes(h.BuildHuffmanTree(calculator.CalculateFrequencies(sourceText)), "", codes)
+	return codes
+}
+
+// HuffmanDecompressor implements the Decompressor interface for payloads of HuffmanCompressor.
+type HuffmanDecompressor struct {
+	lengthLimit
+}
+
+// Decode decodes exactly the stored number of symbols, so padding bits are never mistaken for data.
+// When codeTable is nil the table stored in the payload is used.
+func (d *HuffmanDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(encodedText))
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading length: %w", err)
+	}
+	if err := d.checkLength(length); err != nil {
+		return nil, err
+	}
+	stored, err := huffman.ReadCodeTable(reader)
+	if err != nil {
+		return nil, err
+	}
+	if codeTable == nil {
+		codeTable = stored
+	}
+	if length > 0 && (len(codeTable) == 0 || hasEmptyCode(codeTable)) {
+		return nil, fmt.Errorf("invalid code table")
+	}
+
+	return decodeSymbols(bitio.NewReader(reader), codeTable, length)
+}
+
+// decodeSymbols decodes exactly length symbols from bits with a reverse lookup code table.
+func decodeSymbols(bits *bitio.Reader, codeTable map[string]byte, length uint64) ([]byte, error) {
+	decodedText := make([]byte, 0, minInt(length, 1<<20))
+	var currentCode []byte
+	for uint64(len(decodedText)) < length {
+		bit, err := bits.ReadBit()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding text: unexpected end of data after %d bytes", len(decodedText))
+		}
+		currentCode = append(currentCode, '0'+byte(bit))
+		if char, ok := codeTable[string(currentCode)]; ok {
+			decodedText = append(decodedText, char)
+			currentCode = currentCode[:0]
+		} else if len(currentCode) > 256 {
+			return nil, fmt.Errorf("invalid code: %s", currentCode)
+		}
+	}
+
+	return decodedText, nil
+}
+
+// hasEmptyCode reports whether the table contains the empty code, which would never consume a bit.
+func hasEmptyCode(codeTable map[string]byte) bool {
+	_, ok := codeTable[""]
+	return ok
+}
+
+// minInt returns the smaller of a and b as an int.
+func minInt(a uint64, b int) int {
+	if a < uint64(b) {
+		return int(a)
+	}
+	return b
+}
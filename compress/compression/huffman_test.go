@@ -0,0 +1,63 @@
+package compress
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+)
+
+func TestHuffmanRoundTrip(t *testing.T) {
+	random := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	testCases := []struct {
+		name   string
+		source []byte
+	}{
+		{name: "Empty", source: []byte{}},
+		{name: "SingleSymbol", source: bytes.Repeat([]byte("z"), 10)},
+		{name: "Newlines_and_commas", source: []byte("a,b\nc,,\n\n")},
+		{name: "AllBytes", source: allBytes()},
+		{name: "Random", source: random},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := (&HuffmanCompressor{}).Encode(tc.source, nil)
+			decoded, err := (&HuffmanDecompressor{}).Decode(encoded, nil)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !bytes.Equal(decoded, tc.source) {
+				t.Errorf("Decode() failed, expected: %v, got: %v", tc.source, decoded)
+			}
+		})
+	}
+}
+
+func TestHuffmanIgnoresPadding(t *testing.T) {
+	// 'a' gets a one bit code, so the padding of the last byte would decode as extra 'a's
+	source := []byte("aaaaaaab")
+	encoded := (&HuffmanCompressor{}).Encode(source, nil)
+	decoded, err := (&HuffmanDecompressor{}).Decode(encoded, nil)
+	if err != nil {
+		t.Fatalf("Decode() error: %v", err)
+	}
+	if !bytes.Equal(decoded, source) {
+		t.Errorf("Expected %q, got %q", source, decoded)
+	}
+}
+
+func TestHuffmanDeterministic(t *testing.T) {
+	// Many symbols with equal frequencies, so every tie has to be broken the same way
+	source := bytes.Repeat(allBytes(), 3)
+	source = append(source, []byte("the quick brown fox jumps over the lazy dog")...)
+
+	expected := sha256.Sum256((&HuffmanCompressor{}).Encode(source, nil))
+	for run := 0; run < 100; run++ {
+		if sum := sha256.Sum256((&HuffmanCompressor{}).Encode(source, nil)); sum != expected {
+			t.Fatalf("Expected identical output on run %d, got hash %x instead of %x", run, sum, expected)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Farber98/cc-solutions/compress/frequency"
+	rc "github.com/Farber98/cc-solutions/compress/range_coder"
+)
+
+// Range models, stored as the first byte of the payload.
+const (
+	RangeModelStatic  byte = 0
+	RangeModelContext byte = 1
+)
+
+// RangeCompressor implements the Compressor interface with a range coder. Unlike Huffman it
+// can spend fractional bits per symbol, which pays off on skewed distributions. The codes
+// argument is ignored and may be nil.
+type RangeCompressor struct {
+	// Context selects the adaptive order-1 model instead of the static order-0 model.
+	Context bool
+}
+
+// Encode encodes the source text as model byte, uvarint length, the static frequency table
+// when the static model is used, and the range coded data.
+func (c *RangeCompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	var buffer bytes.Buffer
+
+	var model rc.Model
+	if c.Context {
+		buffer.WriteByte(RangeModelContext)
+		buffer.Write(binary.AppendUvarint(nil, uint64(len(sourceText))))
+		model = rc.NewContextModel()
+	} else {
+		buffer.WriteByte(RangeModelStatic)
+		buffer.Write(binary.AppendUvarint(nil, uint64(len(sourceText))))
+		calculator := &frequency.DefaultCalculator{}
+		static := rc.NewStaticModel(calculator.CalculateFrequencies(sourceText))
+		static.WriteTo(&buffer)
+		model = static
+	}
+
+	// Writes to a bytes.Buffer never fail
+	encoder := rc.NewEncoder(&buffer)
+	prev := byte(0)
+	for _, char := range sourceText {
+		cum, freq, total := model.Range(prev, char)
+		encoder.Encode(cum, freq, total)
+		model.Update(prev, char)
+		prev = char
+	}
+	encoder.Flush()
+
+	return buffer.Bytes()
+}
+
+// RangeDecompressor implements the Decompressor interface with a range coder.
+// The codeTable argument is ignored and may be nil.
+type RangeDecompressor struct {
+	lengthLimit
+}
+
+// Decode decodes data produced by RangeCompressor. It fails before decoding when the stored
+// length is more than the limit set with SetMaxLength.
+func (d *RangeDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(encodedText))
+
+	modelType, err := reader.ReadByte()
+	if err != nil {
+		return nil, fmt.Errorf("error reading range model: %w", err)
+	}
+	length, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading length: %w", err)
+	}
+	if err := d.checkLength(length); err != nil {
+		return nil, err
+	}
+
+	var model rc.Model
+	switch modelType {
+	case RangeModelStatic:
+		model, err = rc.ReadStaticModel(reader)
+		if err != nil {
+			return nil, err
+		}
+	case RangeModelContext:
+		model = rc.NewContextModel()
+	default:
+		return nil, fmt.Errorf("unknown range model: %d", modelType)
+	}
+
+	if length == 0 {
+		return []byte{}, nil
+	}
+
+	decoder, err := rc.NewDecoder(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading range coder state: %w", err)
+	}
+
+	// A corrupt length must not trigger a huge allocation up front
+	capacity := length
+	if capacity > 1<<20 {
+		capacity = 1 << 20
+	}
+	decodedText := make([]byte, 0, capacity)
+	prev := byte(0)
+	for uint64(len(decodedText)) < length {
+		total := model.Total(prev)
+		if total == 0 {
+			return nil, fmt.Errorf("invalid model: empty frequency table")
+		}
+		char, cum, freq := model.Find(prev, decoder.GetFreq(total))
+		if err := decoder.Decode(cum, freq); err != nil {
+			return nil, fmt.Errorf("error decoding text: %w", err)
+		}
+		model.Update(prev, char)
+		decodedText = append(decodedText, char)
+		prev = char
+	}
+
+	return decodedText, nil
+}
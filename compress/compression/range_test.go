@@ -0,0 +1,93 @@
+package compress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestRangeRoundTrip(t *testing.T) {
+	random := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	testCases := []struct {
+		name   string
+		source []byte
+	}{
+		{name: "Empty", source: []byte{}},
+		{name: "SingleSymbol", source: bytes.Repeat([]byte("z"), 1000)},
+		{name: "Text", source: []byte("aabbccddddccbbaaaabbccddddccbbaa")},
+		{name: "AllBytes", source: allBytes()},
+		{name: "Random", source: random},
+		{name: "Skewed", source: append(bytes.Repeat([]byte{0}, 5000), 1, 2, 3)},
+	}
+
+	for _, tc := range testCases {
+		for _, context := range []bool{false, true} {
+			name := tc.name + "/Static"
+			if context {
+				name = tc.name + "/Context"
+			}
+			t.Run(name, func(t *testing.T) {
+				encoded := (&RangeCompressor{Context: context}).Encode(tc.source, nil)
+				decoded, err := (&RangeDecompressor{}).Decode(encoded, nil)
+				if err != nil {
+					t.Fatalf("Decode() error: %v", err)
+				}
+				if !bytes.Equal(decoded, tc.source) {
+					t.Errorf("Decode() failed, expected %d bytes, got %d", len(tc.source), len(decoded))
+				}
+			})
+		}
+	}
+}
+
+func TestRangeBeatsHuffmanOnSkewedInput(t *testing.T) {
+	// 'a' has probability 0.99, Huffman still needs a whole bit for it
+	source := append(bytes.Repeat([]byte("a"), 9900), bytes.Repeat([]byte("b"), 100)...)
+
+	encoded := (&RangeCompressor{}).Encode(source, nil)
+	huffmanBytes := len(source) / 8
+	if len(encoded) >= huffmanBytes/2 {
+		t.Errorf("Expected range coder to use less than %d bytes, got %d", huffmanBytes/2, len(encoded))
+	}
+}
+
+func TestRangeDecodeErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		encoded []byte
+	}{
+		{name: "Empty", encoded: []byte{}},
+		{name: "UnknownModel", encoded: []byte{9, 0}},
+		{name: "Truncated", encoded: (&RangeCompressor{}).Encode([]byte("truncated"), nil)[:6]},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := (&RangeDecompressor{}).Decode(tc.encoded, nil); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestRangeMaxLength(t *testing.T) {
+	source := []byte("bounded by the frame")
+	encoded := (&RangeCompressor{Context: true}).Encode(source, nil)
+	d := &RangeDecompressor{}
+	d.SetMaxLength(uint64(len(source)))
+	if _, err := d.Decode(encoded, nil); err != nil {
+		t.Fatalf("Expected no error at the exact length, got %v", err)
+	}
+
+	// A context model payload of a few bytes can claim any length
+	crafted := append([]byte{RangeModelContext}, binary.AppendUvarint(nil, 200_000_000)...)
+	crafted = append(crafted, 0, 0, 0, 0, 0)
+	_, err := d.Decode(crafted, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid length: 200000000 bytes") {
+		t.Errorf("Expected an error for a length over the limit, got %v", err)
+	}
+}
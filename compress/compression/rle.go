@@ -0,0 +1,148 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+
+	"github.com/Farber98/cc-solutions/compress/bitio"
+	"github.com/Farber98/cc-solutions/compress/huffman"
+)
+
+// Constants of the run-length alphabet.
+const (
+	// rleMinRepeat is the shortest repetition coded as a run symbol instead of literals.
+	rleMinRepeat = 3
+	// rleBuckets is the number of run symbols. Run symbol k codes 2^k to 2^(k+1)-1 repetitions.
+	rleBuckets = 24
+	// rleMaxRepeat is the most repetitions a single run symbol can hold.
+	rleMaxRepeat = 1<<rleBuckets - 1
+	// rleAlphabetSize is the number of symbols: the 256 byte values, then the run symbols.
+	rleAlphabetSize = 256 + rleBuckets
+)
+
+// RLECompressor implements the Compressor interface with Huffman coding over an alphabet of the
+// 256 byte values and run symbols, for sparse and binary data such as disk images and padded
+// records. A run symbol repeats the previous byte: symbol 256+k, followed by the low k bits of
+// the repetition count, stands for 2^k to 2^(k+1)-1 repetitions. Runs sit above the byte values
+// in the alphabet, so no byte has to be escaped, and Huffman gives the common run lengths short
+// codes. The payload is the uvarint symbol count, the code table of AppendSymbolCodeTable and the
+// code bits. The codes argument is ignored and may be nil.
+type RLECompressor struct{}
+
+// Encode encodes the source text as literals and runs.
+func (c *RLECompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	var symbols []int
+	var repeats []int
+	for i := 0; i < len(sourceText); {
+		b := sourceText[i]
+		symbols = append(symbols, int(b))
+		i++
+
+		for {
+			n := 0
+			for i+n < len(sourceText) && sourceText[i+n] == b && n < rleMaxRepeat {
+				n++
+			}
+			if n < rleMinRepeat {
+				break
+			}
+			symbols = append(symbols, 256+bits.Len(uint(n))-1)
+			repeats = append(repeats, n)
+			i += n
+		}
+	}
+
+	frequencies := make(map[int]int)
+	for _, symbol := range symbols {
+		frequencies[symbol]++
+	}
+	symbolCodes := make(map[int]string)
+	h := &huffman.DefaultHuffmanCoding{}
+	h.AssignSymbolCodes(h.BuildSymbolTree(frequencies), "", symbolCodes)
+
+	var buffer bytes.Buffer
+	buffer.Write(binary.AppendUvarint(nil, uint64(len(symbols))))
+	buffer.Write(huffman.AppendSymbolCodeTable(nil, symbolCodes))
+
+	// Writes to a bytes.Buffer never fail
+	writer := bitio.NewWriter(&buffer)
+	for _, symbol := range symbols {
+		for _, bit := range symbolCodes[symbol] {
+			writer.WriteBit(uint(bit - '0'))
+		}
+		if symbol >= 256 {
+			writer.WriteBits(uint64(repeats[0]), symbol-256)
+			repeats = repeats[1:]
+		}
+	}
+	writer.Flush()
+
+	return buffer.Bytes()
+}
+
+// RLEDecompressor implements the Decompressor interface for payloads of RLECompressor.
+// The codeTable argument is ignored and may be nil.
+type RLEDecompressor struct {
+	lengthLimit
+}
+
+// Decode decodes exactly the stored number of symbols. A few bits of run symbols stand for
+// megabytes, so it fails before a run would take the output past the limit set with
+// SetMaxLength.
+func (d *RLEDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(encodedText))
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading symbol count: %w", err)
+	}
+	symbolTable, err := huffman.ReadSymbolCodeTable(reader, rleAlphabetSize)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 && len(symbolTable) == 0 {
+		return nil, fmt.Errorf("invalid code table")
+	}
+
+	bitReader := bitio.NewReader(reader)
+	decodedText := make([]byte, 0, minInt(count, 1<<20))
+	var currentCode []byte
+	for decoded := uint64(0); decoded < count; {
+		bit, err := bitReader.ReadBit()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding text: unexpected end of data after %d symbols", decoded)
+		}
+		currentCode = append(currentCode, '0'+byte(bit))
+		symbol, ok := symbolTable[string(currentCode)]
+		if !ok {
+			if len(currentCode) >= rleAlphabetSize {
+				return nil, fmt.Errorf("invalid code: %s", currentCode)
+			}
+			continue
+		}
+		currentCode = currentCode[:0]
+		decoded++
+
+		if symbol < 256 {
+			decodedText = append(decodedText, byte(symbol))
+			continue
+		}
+		if len(decodedText) == 0 {
+			return nil, fmt.Errorf("invalid run at symbol %d: no byte to repeat", decoded-1)
+		}
+		k := symbol - 256
+		low, err := bitReader.ReadBits(k)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding text: unexpected end of data after %d symbols", decoded)
+		}
+		n := 1<<k | int(low)
+		if err := d.checkLength(uint64(len(decodedText)) + uint64(n)); err != nil {
+			return nil, fmt.Errorf("invalid run at symbol %d: %w", decoded-1, err)
+		}
+		decodedText = append(decodedText, bytes.Repeat(decodedText[len(decodedText)-1:], n)...)
+	}
+
+	return decodedText, nil
+}
@@ -0,0 +1,107 @@
+package compress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// sparseImage returns data like a disk image: small records separated by long runs of zeros.
+func sparseImage() []byte {
+	var buf bytes.Buffer
+	for i := 0; i < 64; i++ {
+		buf.WriteString("record header\x01\x02\x03")
+		buf.Write(make([]byte, 4000+i*37))
+		buf.Write(bytes.Repeat([]byte{0xFF}, 500))
+	}
+	return buf.Bytes()
+}
+
+func TestRLERoundTrip(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source []byte
+	}{
+		{name: "Empty", source: []byte{}},
+		{name: "SingleByte", source: []byte{0}},
+		{name: "Short_repeats", source: []byte("aabbbcccc")},
+		{name: "Long_run", source: make([]byte, rleMaxRepeat+10)},
+		{name: "Runs_of_every_length", source: runsOfEveryLength()},
+		{name: "AllBytes", source: allBytes()},
+		{name: "Text", source: []byte("It was the best of times, it was the worst of times.\n")},
+		{name: "Sparse", source: sparseImage()},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := (&RLECompressor{}).Encode(tc.source, nil)
+			decoded, err := (&RLEDecompressor{}).Decode(encoded, nil)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !bytes.Equal(decoded, tc.source) {
+				t.Errorf("Decode() failed, expected %d bytes, got %d", len(tc.source), len(decoded))
+			}
+		})
+	}
+}
+
+// runsOfEveryLength returns runs of 1 to 300 bytes, so every bucket boundary is crossed.
+func runsOfEveryLength() []byte {
+	var buf bytes.Buffer
+	for n := 1; n <= 300; n++ {
+		buf.Write(bytes.Repeat([]byte{byte(n)}, n))
+	}
+	return buf.Bytes()
+}
+
+func TestRLEBeatsHuffmanOnSparseData(t *testing.T) {
+	source := sparseImage()
+	rle := (&RLECompressor{}).Encode(source, nil)
+	huffman := (&HuffmanCompressor{}).Encode(source, nil)
+	if len(rle)*10 > len(huffman) {
+		t.Errorf("Expected RLE to be a tenth of the %d bytes of Huffman, got %d", len(huffman), len(rle))
+	}
+	t.Logf("source %d bytes, huffman %d bytes, rle %d bytes", len(source), len(huffman), len(rle))
+}
+
+func TestRLEInvalid(t *testing.T) {
+	encoded := (&RLECompressor{}).Encode(sparseImage(), nil)
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		expectedError string
+	}{
+		{name: "Truncated", data: encoded[:len(encoded)-4], expectedError: "unexpected end of data"},
+		// One symbol, run symbol 257 with code "0", then its code bit and repetition bit
+		{name: "Run_without_byte", data: []byte{1, 1, 0x81, 0x02, 1, 0x00, 0x00}, expectedError: "no byte to repeat"},
+		{name: "Symbol_outside_alphabet", data: []byte{1, 1, 0xFF, 0x7F, 1, 0x00}, expectedError: "invalid symbol in code table"},
+		{name: "Missing_table", data: []byte{5, 0}, expectedError: "invalid code table"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := (&RLEDecompressor{}).Decode(tc.data, nil)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestRLEMaxLength(t *testing.T) {
+	source := make([]byte, 1<<20)
+	encoded := (&RLECompressor{}).Encode(source, nil)
+
+	d := &RLEDecompressor{}
+	d.SetMaxLength(1 << 20)
+	if _, err := d.Decode(encoded, nil); err != nil {
+		t.Fatalf("Expected no error at the exact length, got %v", err)
+	}
+	d.SetMaxLength(1<<20 - 1)
+	_, err := d.Decode(encoded, nil)
+	if err == nil || !strings.Contains(err.Error(), "more than the 1048575 allowed") {
+		t.Errorf("Expected an error for a run past the limit, got %v", err)
+	}
+}
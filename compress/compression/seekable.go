@@ -0,0 +1,377 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/Farber98/cc-solutions/compress/bitio"
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/huffman"
+)
+
+// DefaultSyncInterval is the number of uncompressed bytes between two sync points.
+const DefaultSyncInterval = 64 << 10
+
+// SyncPoint records where a block of the uncompressed data starts in the code bits.
+type SyncPoint struct {
+	// BitOffset is the offset of the first code of the block, in bits from the start of the code bits.
+	BitOffset uint64
+	// Offset is the offset of the block in the uncompressed data.
+	Offset uint64
+	// Checksum is the CRC32 (IEEE) of the block.
+	Checksum uint32
+}
+
+// SeekableCompressor implements the Compressor interface with static Huffman coding plus a seek
+// index, so that byte ranges can be decoded without decoding from the start. The payload is the
+// uvarint length, the code table, the uvarint sync interval, the code bits, the index (uvarint
+// count, then uvarint bit offset, uvarint offset and big endian CRC32 of every block) and the
+// big endian 8 byte offset of the index from the start of the payload.
+type SeekableCompressor struct {
+	// Interval is the number of uncompressed bytes between sync points, DefaultSyncInterval when zero.
+	Interval int
+}
+
+// Encode encodes the source text. When codes is nil the table is built from the source text.
+func (c *SeekableCompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	if codes == nil {
+		codes = BuildCodes(sourceText)
+	}
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+
+	buf := binary.AppendUvarint(nil, uint64(len(sourceText)))
+	buf = huffman.AppendCodeTable(buf, codes)
+	buf = binary.AppendUvarint(buf, uint64(interval))
+
+	// Writes to a bytes.Buffer never fail
+	var bits bytes.Buffer
+	w := bitio.NewWriter(&bits)
+	var points []SyncPoint
+	for start := 0; start < len(sourceText); start += interval {
+		end := start + interval
+		if end > len(sourceText) {
+			end = len(sourceText)
+		}
+		points = append(points, SyncPoint{
+			BitOffset: uint64(w.BitsWritten()),
+			Offset:    uint64(start),
+			Checksum:  container.Checksum(sourceText[start:end]),
+		})
+		for _, char := range sourceText[start:end] {
+			for _, bit := range codes[char] {
+				w.WriteBit(uint(bit - '0'))
+			}
+		}
+	}
+	w.Flush()
+	buf = append(buf, bits.Bytes()...)
+
+	indexOffset := len(buf)
+	buf = binary.AppendUvarint(buf, uint64(len(points)))
+	for _, point := range points {
+		buf = binary.AppendUvarint(buf, point.BitOffset)
+		buf = binary.AppendUvarint(buf, point.Offset)
+		buf = binary.BigEndian.AppendUint32(buf, point.Checksum)
+	}
+	return binary.BigEndian.AppendUint64(buf, uint64(indexOffset))
+}
+
+// SeekableDecompressor implements the Decompressor interface for payloads of SeekableCompressor.
+// The codeTable argument is ignored and may be nil.
+type SeekableDecompressor struct {
+	lengthLimit
+}
+
+// Decode decodes the whole payload.
+func (d *SeekableDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	reader, err := newSeekablePayload(bytes.NewReader(encodedText), 0, int64(len(encodedText)))
+	if err != nil {
+		return nil, err
+	}
+	if err := d.checkLength(uint64(reader.length)); err != nil {
+		return nil, err
+	}
+	decodedText := make([]byte, reader.length)
+	if _, err := reader.ReadAt(decodedText, 0); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return decodedText, nil
+}
+
+// SeekReader decodes byte ranges of a frame written with SeekableCompressor. Only the blocks
+// holding the requested bytes are decoded, each verified against its checksum. It implements
+// io.Reader, io.Seeker and io.ReaderAt. ReadAt may be called concurrently.
+type SeekReader struct {
+	r         io.ReaderAt
+	bitsStart int64
+	bitsEnd   int64
+	length    int64
+	codeTable map[string]byte
+	points    []SyncPoint
+
+	// Position for Read and Seek
+	offset int64
+
+	// Last decoded block
+	mu         sync.Mutex
+	block      []byte
+	blockIndex int
+}
+
+// NewSeekReader reads the frame header and the seek index of the first frame in r, which is
+// size bytes long.
+func NewSeekReader(r io.ReaderAt, size int64) (*SeekReader, error) {
+	section := io.NewSectionReader(r, 0, size)
+	header, err := container.ReadHeader(section)
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+	if header.Method != container.MethodSeekable {
+		return nil, fmt.Errorf("frame has no seek index, it was compressed with %v", header.Method)
+	}
+	if len(header.Stages) > 0 {
+		return nil, fmt.Errorf("frame has stages, random access is not possible")
+	}
+
+	payloadStart, _ := section.Seek(0, io.SeekCurrent)
+	payloadEnd := size
+	if header.Sized {
+		// Frames may follow, the index is at the end of this one
+		if header.PayloadSize > uint64(size-payloadStart) {
+			return nil, fmt.Errorf("truncated frame: expected %d payload bytes, got %d", header.PayloadSize, size-payloadStart)
+		}
+		payloadEnd = payloadStart + int64(header.PayloadSize)
+	}
+	reader, err := newSeekablePayload(r, payloadStart, payloadEnd)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(reader.length) != header.Length {
+		return nil, fmt.Errorf("%w: expected %d bytes, payload holds %d", container.ErrChecksum, header.Length, reader.length)
+	}
+	return reader, nil
+}
+
+// newSeekablePayload reads the payload header and the index of a payload at [start, end) of r.
+func newSeekablePayload(r io.ReaderAt, start, end int64) (*SeekReader, error) {
+	if end-start < 8 {
+		return nil, fmt.Errorf("error reading seek index: %w", io.ErrUnexpectedEOF)
+	}
+
+	counter := &countingReader{r: bufio.NewReader(io.NewSectionReader(r, start, end-start))}
+	length, err := binary.ReadUvarint(counter)
+	if err != nil {
+		return nil, fmt.Errorf("error reading length: %w", err)
+	}
+	codeTable, err := huffman.ReadCodeTable(counter)
+	if err != nil {
+		return nil, err
+	}
+	if length > 0 && (len(codeTable) == 0 || hasEmptyCode(codeTable)) {
+		return nil, fmt.Errorf("invalid code table")
+	}
+	interval, err := binary.ReadUvarint(counter)
+	if err != nil {
+		return nil, fmt.Errorf("error reading sync interval: %w", err)
+	}
+	if interval == 0 || interval > 1<<62 {
+		return nil, fmt.Errorf("invalid sync interval: %d", interval)
+	}
+
+	trailer := make([]byte, 8)
+	if _, err := r.ReadAt(trailer, end-8); err != nil {
+		return nil, fmt.Errorf("error reading seek index: %w", err)
+	}
+	indexOffset := binary.BigEndian.Uint64(trailer)
+	if indexOffset < uint64(counter.n) || indexOffset > uint64(end-start-8) {
+		return nil, fmt.Errorf("invalid seek index offset: %d", indexOffset)
+	}
+	// Every byte takes at least one bit, so a corrupt length cannot make decoding allocate more
+	// than the code bits can hold
+	bits := (indexOffset - uint64(counter.n)) * 8
+	if length > bits {
+		return nil, fmt.Errorf("invalid length: %d bytes from %d code bits", length, bits)
+	}
+
+	s := &SeekReader{
+		r:          r,
+		bitsStart:  start + counter.n,
+		bitsEnd:    start + int64(indexOffset),
+		length:     int64(length),
+		codeTable:  codeTable,
+		blockIndex: -1,
+	}
+	if s.points, err = readIndex(io.NewSectionReader(r, s.bitsEnd, end-8-s.bitsEnd), bits, length, interval); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// readIndex reads the sync points and checks that they are ordered and within bounds, with
+// blocks of at most interval bytes.
+func readIndex(r io.Reader, bits, length, interval uint64) ([]SyncPoint, error) {
+	br := bufio.NewReader(r)
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("error reading seek index: %w", err)
+	}
+	if (length == 0) != (count == 0) || count > length {
+		return nil, fmt.Errorf("invalid seek index size: %d", count)
+	}
+
+	var points []SyncPoint
+	checksum := make([]byte, 4)
+	for i := uint64(0); i < count; i++ {
+		var point SyncPoint
+		if point.BitOffset, err = binary.ReadUvarint(br); err != nil {
+			return nil, fmt.Errorf("error reading seek index: %w", err)
+		}
+		if point.Offset, err = binary.ReadUvarint(br); err != nil {
+			return nil, fmt.Errorf("error reading seek index: %w", err)
+		}
+		if _, err := io.ReadFull(br, checksum); err != nil {
+			return nil, fmt.Errorf("error reading seek index: %w", err)
+		}
+		point.Checksum = binary.BigEndian.Uint32(checksum)
+
+		valid := point.BitOffset <= bits && point.Offset < length
+		if i == 0 {
+			valid = valid && point.Offset == 0 && point.BitOffset == 0
+		} else {
+			prev := points[i-1]
+			valid = valid && point.Offset > prev.Offset && point.Offset-prev.Offset <= interval && point.BitOffset > prev.BitOffset
+		}
+		if !valid {
+			return nil, fmt.Errorf("invalid sync point %d", i)
+		}
+		points = append(points, point)
+	}
+	if count > 0 && length-points[count-1].Offset > interval {
+		return nil, fmt.Errorf("invalid length: %d bytes from %d blocks of %d", length, count, interval)
+	}
+	return points, nil
+}
+
+// Size returns the length of the uncompressed data.
+func (s *SeekReader) Size() int64 {
+	return s.length
+}
+
+// SyncPoints returns the sync points of the index.
+func (s *SeekReader) SyncPoints() []SyncPoint {
+	return s.points
+}
+
+// ReadAt implements io.ReaderAt.
+func (s *SeekReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+
+	n := 0
+	for n < len(p) && off < s.length {
+		// The last sync point at or before off starts the block holding off
+		i := sort.Search(len(s.points), func(i int) bool { return int64(s.points[i].Offset) > off }) - 1
+		block, err := s.decodeBlock(i)
+		if err != nil {
+			return n, err
+		}
+		copied := copy(p[n:], block[off-int64(s.points[i].Offset):])
+		n += copied
+		off += int64(copied)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// decodeBlock decodes and verifies block i, reusing the last decoded block.
+func (s *SeekReader) decodeBlock(i int) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if i == s.blockIndex {
+		return s.block, nil
+	}
+
+	point := s.points[i]
+	end := uint64(s.length)
+	if i+1 < len(s.points) {
+		end = s.points[i+1].Offset
+	}
+
+	start := s.bitsStart + int64(point.BitOffset/8)
+	bits := bitio.NewReader(bufio.NewReader(io.NewSectionReader(s.r, start, s.bitsEnd-start)))
+	if _, err := bits.ReadBits(int(point.BitOffset % 8)); err != nil {
+		return nil, fmt.Errorf("error decoding block %d: %w", i, err)
+	}
+	block, err := decodeSymbols(bits, s.codeTable, end-point.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding block %d: %w", i, err)
+	}
+	if sum := container.Checksum(block); sum != point.Checksum {
+		return nil, fmt.Errorf("%w: checksum mismatch in block %d at offset %d", container.ErrChecksum, i, point.Offset)
+	}
+
+	s.block, s.blockIndex = block, i
+	return block, nil
+}
+
+// Read implements io.Reader.
+func (s *SeekReader) Read(p []byte) (int, error) {
+	if s.offset >= s.length {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > s.length-s.offset {
+		p = p[:s.length-s.offset]
+	}
+	n, err := s.ReadAt(p, s.offset)
+	s.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+// Seek implements io.Seeker.
+func (s *SeekReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += s.offset
+	case io.SeekEnd:
+		offset += s.length
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	s.offset = offset
+	return offset, nil
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+// ReadByte implements io.ByteReader.
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
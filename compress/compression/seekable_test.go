@@ -0,0 +1,217 @@
+package compress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/container"
+)
+
+// seekableFrame returns a complete frame of source compressed with a seek index.
+func seekableFrame(t *testing.T, source []byte, interval int) []byte {
+	t.Helper()
+	var frame bytes.Buffer
+	header := container.Header{Method: container.MethodSeekable, Length: uint64(len(source)), Checksum: container.Checksum(source)}
+	if err := container.WriteHeader(&frame, header); err != nil {
+		t.Fatal(err)
+	}
+	frame.Write((&SeekableCompressor{Interval: interval}).Encode(source, nil))
+	return frame.Bytes()
+}
+
+// logLines returns n numbered log lines.
+func logLines(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "2024-01-01T00:00:%02d line %d status=%d\n", i%60, i, 200+i%3)
+	}
+	return buf.Bytes()
+}
+
+func TestSeekableRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   []byte
+		interval int
+	}{
+		{name: "Empty", source: []byte{}},
+		{name: "SingleSymbol", source: bytes.Repeat([]byte("z"), 100), interval: 7},
+		{name: "AllBytes", source: allBytes(), interval: 16},
+		{name: "Logs", source: logLines(500), interval: 1000},
+		{name: "DefaultInterval", source: logLines(5000)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := (&SeekableCompressor{Interval: tc.interval}).Encode(tc.source, nil)
+			decoded, err := (&SeekableDecompressor{}).Decode(encoded, nil)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !bytes.Equal(decoded, tc.source) {
+				t.Errorf("Decode() failed, expected %d bytes, got %d", len(tc.source), len(decoded))
+			}
+		})
+	}
+}
+
+func TestSeekReader_ReadAt(t *testing.T) {
+	source := logLines(2000)
+	frame := seekableFrame(t, source, 4096)
+
+	reader, err := NewSeekReader(bytes.NewReader(frame), int64(len(frame)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reader.Size() != int64(len(source)) {
+		t.Errorf("Expected size %d, got %d", len(source), reader.Size())
+	}
+	if expected := (len(source) + 4095) / 4096; len(reader.SyncPoints()) != expected {
+		t.Errorf("Expected %d sync points, got %d", expected, len(reader.SyncPoints()))
+	}
+
+	random := rand.New(rand.NewSource(40))
+	for i := 0; i < 200; i++ {
+		off := random.Intn(len(source))
+		p := make([]byte, random.Intn(10000))
+		n, err := reader.ReadAt(p, int64(off))
+
+		expected := source[off:]
+		if len(expected) > len(p) {
+			expected = expected[:len(p)]
+		}
+		if !bytes.Equal(p[:n], expected) {
+			t.Fatalf("ReadAt(%d, %d) returned wrong data", len(p), off)
+		}
+		if n < len(p) && err != io.EOF {
+			t.Fatalf("Expected io.EOF for a short read, got %v", err)
+		}
+		if n == len(p) && err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+}
+
+func TestSeekReader_Tail(t *testing.T) {
+	source := logLines(2000)
+	frame := seekableFrame(t, source, 1024)
+
+	reader, err := NewSeekReader(bytes.NewReader(frame), int64(len(frame)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := reader.Seek(-100, io.SeekEnd); err != nil {
+		t.Fatal(err)
+	}
+	tail, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(tail, source[len(source)-100:]) {
+		t.Errorf("Expected %q, got %q", source[len(source)-100:], tail)
+	}
+}
+
+func TestSeekableDecompressor_CorruptLength(t *testing.T) {
+	source := logLines(10)
+	payload := (&SeekableCompressor{Interval: 64}).Encode(source, nil)
+	blocks := uint64(len(source)+63) / 64
+
+	// withLength returns the payload with its length replaced, moving the index offset along
+	withLength := func(length uint64) []byte {
+		_, n := binary.Uvarint(payload)
+		corrupt := binary.AppendUvarint(nil, length)
+		shift := len(corrupt) - n
+		corrupt = append(corrupt, payload[n:len(payload)-8]...)
+		indexOffset := binary.BigEndian.Uint64(payload[len(payload)-8:])
+		return binary.BigEndian.AppendUint64(corrupt, uint64(int64(indexOffset)+int64(shift)))
+	}
+
+	testCases := []struct {
+		name          string
+		length        uint64
+		expectedError string
+	}{
+		{name: "Huge", length: 1 << 61, expectedError: "code bits"},
+		{name: "Past_the_blocks", length: blocks*64 + 1, expectedError: "blocks of 64"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := (&SeekableDecompressor{}).Decode(withLength(tc.length), nil)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestSeekReader_CorruptBlock(t *testing.T) {
+	source := logLines(200)
+	frame := seekableFrame(t, source, 1000)
+
+	reader, err := NewSeekReader(bytes.NewReader(frame), int64(len(frame)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	points := reader.SyncPoints()
+
+	// Flip a bit in the middle of the third block
+	corrupt := append([]byte{}, frame...)
+	corrupt[reader.bitsStart+int64(points[2].BitOffset/8)+50] ^= 0x10
+	reader, err = NewSeekReader(bytes.NewReader(corrupt), int64(len(corrupt)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other blocks still decode
+	p := make([]byte, 100)
+	if _, err := reader.ReadAt(p, 0); err != nil || !bytes.Equal(p, source[:100]) {
+		t.Errorf("Expected the first block to decode, got %v", err)
+	}
+	if _, err := reader.ReadAt(p, int64(points[2].Offset)); err == nil {
+		t.Error("Expected an error for the corrupt block")
+	}
+}
+
+func TestNewSeekReaderErrors(t *testing.T) {
+	source := logLines(10)
+	frame := seekableFrame(t, source, 64)
+
+	var huffmanFrame bytes.Buffer
+	container.WriteHeader(&huffmanFrame, container.Header{Method: container.MethodHuffman, Length: uint64(len(source))})
+	huffmanFrame.Write((&HuffmanCompressor{}).Encode(source, nil))
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		expectedError string
+	}{
+		{name: "No_index", data: huffmanFrame.Bytes(), expectedError: "frame has no seek index"},
+		{name: "Truncated", data: frame[:len(frame)-3], expectedError: "invalid seek index"},
+		{name: "Bad_index_offset", data: append(append([]byte{}, frame[:len(frame)-8]...), 0xff, 0, 0, 0, 0, 0, 0, 0), expectedError: "invalid seek index offset"},
+		{name: "Not_a_frame", data: []byte("plain text"), expectedError: "error reading header"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewSeekReader(bytes.NewReader(tc.data), int64(len(tc.data)))
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+
+	// A frame whose header length disagrees with the payload
+	wrongLength := append([]byte{}, frame...)
+	wrongLength[4]++
+	if _, err := NewSeekReader(bytes.NewReader(wrongLength), int64(len(wrongLength))); !errors.Is(err, container.ErrChecksum) {
+		t.Errorf("Expected ErrChecksum, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/Farber98/cc-solutions/compress/bitio"
+	"github.com/Farber98/cc-solutions/compress/huffman"
+	"github.com/Farber98/cc-solutions/compress/token"
+)
+
+// WordCompressor implements the Compressor interface with Huffman coding over words and
+// separators instead of bytes. Natural language repeats whole words, so a word costs a single
+// code. The payload is the uvarint token count, the vocabulary header and the code bits.
+// The codes argument is ignored and may be nil.
+type WordCompressor struct{}
+
+// Encode encodes the source text token by token.
+func (c *WordCompressor) Encode(sourceText []byte, codes map[byte]string) []byte {
+	vocabulary := token.NewVocabulary(token.Tokenize(sourceText))
+
+	symbolCodes := make(map[int]string)
+	h := &huffman.DefaultHuffmanCoding{}
+	h.AssignSymbolCodes(h.BuildSymbolTree(vocabulary.Frequencies), "", symbolCodes)
+
+	var buffer bytes.Buffer
+	buffer.Write(binary.AppendUvarint(nil, uint64(len(vocabulary.Symbols))))
+	buffer.Write(token.AppendVocabulary(nil, vocabulary.Tokens, symbolCodes))
+
+	// Writes to a bytes.Buffer never fail
+	bits := bitio.NewWriter(&buffer)
+	for _, symbol := range vocabulary.Symbols {
+		for _, bit := range symbolCodes[symbol] {
+			bits.WriteBit(uint(bit - '0'))
+		}
+	}
+	bits.Flush()
+
+	return buffer.Bytes()
+}
+
+// WordDecompressor implements the Decompressor interface for payloads of WordCompressor.
+// The codeTable argument is ignored and may be nil.
+type WordDecompressor struct {
+	lengthLimit
+}
+
+// Decode decodes exactly the stored number of tokens. A short code can stand for a long token,
+// so it fails before the output would go past the limit set with SetMaxLength.
+func (d *WordDecompressor) Decode(encodedText []byte, codeTable map[string]byte) ([]byte, error) {
+	reader := bufio.NewReader(bytes.NewReader(encodedText))
+	count, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading token count: %w", err)
+	}
+	tokens, symbolTable, err := token.ReadVocabulary(reader)
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 && len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid vocabulary")
+	}
+
+	bits := bitio.NewReader(reader)
+	decodedText := make([]byte, 0, minInt(count, 1<<20))
+	var currentCode []byte
+	for decoded := uint64(0); decoded < count; {
+		bit, err := bits.ReadBit()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding text: unexpected end of data after %d tokens", decoded)
+		}
+		currentCode = append(currentCode, '0'+byte(bit))
+		if symbol, ok := symbolTable[string(currentCode)]; ok {
+			if err := d.checkLength(uint64(len(decodedText) + len(tokens[symbol]))); err != nil {
+				return nil, fmt.Errorf("invalid token %d: %w", decoded, err)
+			}
+			decodedText = append(decodedText, tokens[symbol]...)
+			currentCode = currentCode[:0]
+			decoded++
+		} else if len(currentCode) > 256 {
+			return nil, fmt.Errorf("invalid code: %s", currentCode)
+		}
+	}
+
+	return decodedText, nil
+}
@@ -0,0 +1,56 @@
+package compress
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestWordRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name   string
+		source []byte
+	}{
+		{name: "Empty", source: []byte{}},
+		{name: "SingleToken", source: []byte("word")},
+		{name: "RepeatedToken", source: bytes.Repeat([]byte("la "), 50)},
+		{name: "Text", source: []byte("It was the best of times, it was the worst of times.\n")},
+		{name: "AllBytes", source: allBytes()},
+		// Runs over the 1 MiB token limit are split into several tokens
+		{name: "LongRun", source: make([]byte, 2_000_000)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded := (&WordCompressor{}).Encode(tc.source, nil)
+			decoded, err := (&WordDecompressor{}).Decode(encoded, nil)
+			if err != nil {
+				t.Fatalf("Decode() error: %v", err)
+			}
+			if !bytes.Equal(decoded, tc.source) {
+				t.Errorf("Decode() failed, expected: %q, got: %q", tc.source, decoded)
+			}
+		})
+	}
+}
+
+func TestWordBeatsBytesOnText(t *testing.T) {
+	source, err := os.ReadFile("../tests/test.txt")
+	if err != nil {
+		t.Skip("sample text not available:", err)
+	}
+
+	words := (&WordCompressor{}).Encode(source, nil)
+	bytesCoded := (&HuffmanCompressor{}).Encode(source, nil)
+	if len(words) >= len(bytesCoded) {
+		t.Errorf("Expected word coding to beat %d bytes, got %d", len(bytesCoded), len(words))
+	}
+	t.Logf("huffman %d bytes, word %d bytes", len(bytesCoded), len(words))
+}
+
+func TestWordTruncated(t *testing.T) {
+	encoded := (&WordCompressor{}).Encode([]byte("one two three four five six"), nil)
+	if _, err := (&WordDecompressor{}).Decode(encoded[:len(encoded)-2], nil); err == nil {
+		t.Error("Expected an error for truncated data")
+	}
+}
@@ -0,0 +1,249 @@
+package container
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// Magic identifies a framed compressed stream. Files without it use the legacy table header.
+const Magic = "HZ"
+
+// Method identifies the entropy coder used for the payload of a frame.
+type Method byte
+
+// Available methods.
+const (
+	MethodHuffman    Method = 'H'
+	MethodAdaptive   Method = 'A'
+	MethodRange      Method = 'R'
+	MethodDictionary Method = 'D' // static Huffman with the table of a pre-trained dictionary
+	MethodWord       Method = 'W' // static Huffman over words and separators
+	MethodSeekable   Method = 'S' // static Huffman with a seek index for random access
+	MethodBlocks     Method = 'B' // static Huffman in self contained blocks behind sync markers
+	MethodRLE        Method = 'L' // Huffman over the byte values and run-length symbols
+	MethodDelta      Method = 'V' // copies from a reference file plus Huffman coded literals
+	MethodEncrypted  Method = 'E' // an encrypted frame, see the encryption package
+)
+
+// String returns the name of the method as used on the command line.
+func (m Method) String() string {
+	switch m {
+	case MethodHuffman:
+		return "huffman"
+	case MethodAdaptive:
+		return "adaptive"
+	case MethodRange:
+		return "range"
+	case MethodDictionary:
+		return "dictionary"
+	case MethodWord:
+		return "word"
+	case MethodSeekable:
+		return "seekable"
+	case MethodBlocks:
+		return "blocks"
+	case MethodRLE:
+		return "rle"
+	case MethodDelta:
+		return "delta"
+	case MethodEncrypted:
+		return "encrypted"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(m))
+	}
+}
+
+// sizedFlag is set in the stage count byte of frames that record their payload size.
+const sizedFlag = 0x80
+
+// ErrEncrypted is returned by ReadHeader for an encrypted frame, which has to be decrypted first.
+var ErrEncrypted = errors.New("frame is encrypted")
+
+// ErrChecksum is returned when decoded data does not match the length or checksum stored in its frame.
+var ErrChecksum = errors.New("data is corrupt")
+
+// Header describes how the payload of a frame was produced and what it decodes to.
+type Header struct {
+	Method Method
+	// Stages holds the IDs of the pipeline stages applied before entropy coding, in order.
+	Stages []byte
+	// Length is the size of the original data.
+	Length uint64
+	// Checksum is the CRC32 (IEEE) of the original data.
+	Checksum uint32
+	// Sized is set when the header records PayloadSize, so that another frame can follow.
+	// Without it, as in frames written by older versions, the payload runs to the end of the data.
+	Sized bool
+	// PayloadSize is the size of the payload in bytes, when Sized is set.
+	PayloadSize uint64
+}
+
+// Checksum returns the checksum stored in a frame header for data.
+func Checksum(data []byte) uint32 {
+	return crc32.ChecksumIEEE(data)
+}
+
+// Verify checks decoded data against the length and checksum of the header.
+func (h Header) Verify(data []byte) error {
+	if uint64(len(data)) != h.Length {
+		return fmt.Errorf("%w: expected %d bytes, got %d", ErrChecksum, h.Length, len(data))
+	}
+	if sum := Checksum(data); sum != h.Checksum {
+		return fmt.Errorf("%w: checksum mismatch, expected %08x, got %08x", ErrChecksum, h.Checksum, sum)
+	}
+	return nil
+}
+
+// IsEncrypted reports whether data starts with the header of an encrypted frame.
+func IsEncrypted(data []byte) bool {
+	return IsFramed(data) && len(data) > len(Magic) && Method(data[len(Magic)]) == MethodEncrypted
+}
+
+// IsFramed reports whether data starts with the frame magic.
+func IsFramed(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(Magic))
+}
+
+// EncryptedHeaderSize is the size of the header of an encrypted frame written by package
+// encryption, up to its ciphertext size: magic, method, KDF, scrypt parameters, 16 byte salt and
+// 12 byte nonce.
+const EncryptedHeaderSize = len(Magic) + 1 + 1 + 3 + 16 + 12
+
+// EncryptedSizedFlag is set in the KDF byte of encrypted frames whose header goes on with the
+// uvarint size of the ciphertext.
+const EncryptedSizedFlag = 0x80
+
+// EncryptedSize returns the size of the encrypted frame whose header is at the start of data,
+// which must hold the encryption header and the ciphertext size. It returns false for frames
+// written before the ciphertext size was recorded, which run to the end of the data.
+func EncryptedSize(data []byte) (uint64, bool, error) {
+	if len(data) < EncryptedHeaderSize {
+		return 0, false, fmt.Errorf("error reading encryption header: %w", io.ErrUnexpectedEOF)
+	}
+	if data[len(Magic)+1]&EncryptedSizedFlag == 0 {
+		return 0, false, nil
+	}
+	size, n := binary.Uvarint(data[EncryptedHeaderSize:])
+	if n <= 0 || size > math.MaxInt64-uint64(EncryptedHeaderSize+n) {
+		return 0, false, fmt.Errorf("error reading encryption header: invalid ciphertext size")
+	}
+	return uint64(EncryptedHeaderSize+n) + size, true, nil
+}
+
+// FrameSize returns the size of the frame at the start of data, so that the frame after it can
+// be found. Unsized frames, and encrypted frames written before their size was recorded, run to
+// the end of data.
+func FrameSize(data []byte) (int, error) {
+	if IsEncrypted(data) {
+		size, sized, err := EncryptedSize(data)
+		if err != nil || !sized {
+			return len(data), err
+		}
+		if size > uint64(len(data)) {
+			return 0, fmt.Errorf("truncated frame: expected %d encrypted bytes, got %d", size, len(data))
+		}
+		return int(size), nil
+	}
+
+	reader := bytes.NewReader(data)
+	h, err := ReadHeader(reader)
+	if err != nil {
+		return 0, err
+	}
+	if !h.Sized {
+		return len(data), nil
+	}
+	if h.PayloadSize > uint64(reader.Len()) {
+		return 0, fmt.Errorf("truncated frame: expected %d payload bytes, got %d", h.PayloadSize, reader.Len())
+	}
+	return len(data) - reader.Len() + int(h.PayloadSize), nil
+}
+
+// WriteHeader writes the frame magic, the method, the number of stages, the stage IDs,
+// the uvarint length, the big endian checksum and, for sized frames, the uvarint payload size.
+// The high bit of the stage count marks a sized frame.
+func WriteHeader(w io.Writer, h Header) error {
+	if len(h.Stages) >= sizedFlag {
+		return fmt.Errorf("too many stages: %d", len(h.Stages))
+	}
+
+	count := byte(len(h.Stages))
+	if h.Sized {
+		count |= sizedFlag
+	}
+	header := append([]byte(Magic), byte(h.Method), count)
+	header = append(header, h.Stages...)
+	header = binary.AppendUvarint(header, h.Length)
+	header = binary.BigEndian.AppendUint32(header, h.Checksum)
+	if h.Sized {
+		header = binary.AppendUvarint(header, h.PayloadSize)
+	}
+	_, err := w.Write(header)
+	return err
+}
+
+// ReadHeader reads and validates a frame header, leaving r at the start of the payload.
+func ReadHeader(r io.Reader) (Header, error) {
+	fixed := make([]byte, len(Magic)+2)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return Header{}, fmt.Errorf("error reading frame header: %w", err)
+	}
+	if !IsFramed(fixed) {
+		return Header{}, fmt.Errorf("invalid frame magic")
+	}
+
+	h := Header{Method: Method(fixed[len(Magic)])}
+	switch h.Method {
+	case MethodHuffman, MethodAdaptive, MethodRange, MethodDictionary, MethodWord, MethodSeekable, MethodBlocks, MethodRLE, MethodDelta:
+	case MethodEncrypted:
+		return Header{}, ErrEncrypted
+	default:
+		return Header{}, fmt.Errorf("unknown method: %d", byte(h.Method))
+	}
+
+	count := fixed[len(Magic)+1]
+	h.Sized = count&sizedFlag != 0
+	h.Stages = make([]byte, count&^sizedFlag)
+	if _, err := io.ReadFull(r, h.Stages); err != nil {
+		return Header{}, fmt.Errorf("error reading frame stages: %w", err)
+	}
+
+	length, err := binary.ReadUvarint(byteReader{r})
+	if err != nil {
+		return Header{}, fmt.Errorf("error reading frame length: %w", err)
+	}
+	h.Length = length
+
+	checksum := make([]byte, 4)
+	if _, err := io.ReadFull(r, checksum); err != nil {
+		return Header{}, fmt.Errorf("error reading frame checksum: %w", err)
+	}
+	h.Checksum = binary.BigEndian.Uint32(checksum)
+
+	if h.Sized {
+		if h.PayloadSize, err = binary.ReadUvarint(byteReader{r}); err != nil {
+			return Header{}, fmt.Errorf("error reading frame payload size: %w", err)
+		}
+	}
+	return h, nil
+}
+
+// byteReader reads single bytes from r without buffering ahead, so r stays at the payload.
+type byteReader struct {
+	r io.Reader
+}
+
+// ReadByte implements io.ByteReader.
+func (b byteReader) ReadByte() (byte, error) {
+	if br, ok := b.r.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+	var buf [1]byte
+	_, err := io.ReadFull(b.r, buf[:])
+	return buf[0], err
+}
@@ -0,0 +1,137 @@
+package container
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeaderRoundTrip(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header Header
+	}{
+		{name: "No_stages", header: Header{Method: MethodAdaptive, Stages: []byte{}}},
+		{name: "With_stages", header: Header{Method: MethodHuffman, Stages: []byte("BMZ"), Length: 1 << 40, Checksum: 0xdeadbeef}},
+		{name: "Sized", header: Header{Method: MethodRange, Stages: []byte("B"), Length: 300, Checksum: 1, Sized: true, PayloadSize: 200}},
+		{name: "Sized_empty", header: Header{Method: MethodHuffman, Stages: []byte{}, Sized: true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteHeader(&buf, tc.header); err != nil {
+				t.Fatal(err)
+			}
+			if !IsFramed(buf.Bytes()) {
+				t.Error("Expected written header to be detected as framed")
+			}
+
+			header, err := ReadHeader(&buf)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(header, tc.header) {
+				t.Errorf("Expected header %v, got %v", tc.header, header)
+			}
+		})
+	}
+}
+
+func TestReadHeaderErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		contents      string
+		expectedError string
+	}{
+		{name: "Legacy_header", contents: "HS\n0\nHE\n", expectedError: "invalid frame magic"},
+		{name: "Unknown_method", contents: Magic + "?\x00", expectedError: "unknown method"},
+		{name: "Encrypted", contents: Magic + "E\x01", expectedError: "frame is encrypted"},
+		{name: "Truncated", contents: "H", expectedError: "error reading frame header"},
+		{name: "Truncated_stages", contents: Magic + "H\x02B", expectedError: "error reading frame stages"},
+		{name: "Missing_length", contents: Magic + "H\x00", expectedError: "error reading frame length"},
+		{name: "Truncated_checksum", contents: Magic + "H\x00\x05\x01\x02", expectedError: "error reading frame checksum"},
+		{name: "Missing_payload_size", contents: Magic + "H\x80\x05\x01\x02\x03\x04", expectedError: "error reading frame payload size"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadHeader(strings.NewReader(tc.contents))
+			if err == nil {
+				t.Fatal("Expected an error but got nil")
+			}
+			if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error message to contain %q, got %q", tc.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	data := []byte("original data")
+	header := Header{Method: MethodHuffman, Length: uint64(len(data)), Checksum: Checksum(data)}
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		expectedError string
+	}{
+		{name: "Valid", data: data},
+		{name: "Wrong_length", data: append([]byte("extra "), data...), expectedError: "expected 13 bytes, got 19"},
+		{name: "Wrong_checksum", data: []byte("Original data"), expectedError: "checksum mismatch"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := header.Verify(tc.data)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrChecksum) {
+				t.Errorf("Expected ErrChecksum, got %v", err)
+			} else if !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error message to contain %q, got %q", tc.expectedError, err.Error())
+			}
+		})
+	}
+}
+
+func TestFrameSizeEncrypted(t *testing.T) {
+	header := append([]byte(Magic), byte(MethodEncrypted), 1|EncryptedSizedFlag, 15, 8, 1)
+	header = append(header, make([]byte, 16+12)...)
+	sized := append(append(header, 3), "abc"...)
+	unsized := append([]byte{}, header...)
+	unsized[len(Magic)+1] = 1
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		expectedSize  int
+		expectedError string
+	}{
+		{name: "Followed_by_frame", data: append(append([]byte{}, sized...), Magic+"H\x00"...), expectedSize: len(sized)},
+		{name: "Unsized", data: append(unsized, "abc"+Magic...), expectedSize: len(unsized) + 3 + len(Magic)},
+		{name: "Truncated", data: sized[:len(sized)-1], expectedError: "truncated frame"},
+		{name: "Missing_size", data: header, expectedError: "invalid ciphertext size"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			size, err := FrameSize(tc.data)
+			if tc.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil || size != tc.expectedSize {
+				t.Errorf("Expected size %d, got %d and error %v", tc.expectedSize, size, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package dictionary
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/Farber98/cc-solutions/compress/frequency"
+	"github.com/Farber98/cc-solutions/compress/huffman"
+)
+
+// Magic identifies a dictionary file.
+const Magic = "HZDT"
+
+// ErrMismatch is returned when data references a different dictionary than the one given.
+var ErrMismatch = errors.New("dictionary mismatch")
+
+// Dictionary is a pre-trained Huffman code table. Compressed data references it by ID instead
+// of carrying its own table, which matters for small files where the table dominates the size.
+type Dictionary struct {
+	// ID is the CRC32 (IEEE) of the encoded code table.
+	ID uint32
+	// Codes holds a code for every byte value, so any input can be encoded.
+	Codes map[byte]string
+}
+
+// Train builds a dictionary from the byte frequencies of the samples. Every byte value is
+// counted once more than it occurs, so bytes missing from the samples still get a code.
+func Train(samples [][]byte) *Dictionary {
+	calculator := &frequency.DefaultCalculator{}
+	frequencies := make(map[byte]int, 256)
+	for char := 0; char < 256; char++ {
+		frequencies[byte(char)] = 1
+	}
+	for _, sample := range samples {
+		for char, freq := range calculator.CalculateFrequencies(sample) {
+			frequencies[char] += freq
+		}
+	}
+
+	codes := make(map[byte]string, 256)
+	h := &huffman.DefaultHuffmanCoding{}
+	h.AssignCodes(h.BuildHuffmanTree(frequencies), "", codes)
+	return New(codes)
+}
+
+// New creates a dictionary from a code table and computes its ID.
+func New(codes map[byte]string) *Dictionary {
+	return &Dictionary{ID: crc32.ChecksumIEEE(huffman.AppendCodeTable(nil, codes)), Codes: codes}
+}
+
+// ReverseLookup returns the code table mapping codes to bytes, as used for decoding.
+func (d *Dictionary) ReverseLookup() map[string]byte {
+	codeTable := make(map[string]byte, len(d.Codes))
+	for char, code := range d.Codes {
+		codeTable[code] = char
+	}
+	return codeTable
+}
+
+// WriteTo writes the dictionary as magic, big endian ID and the binary code table.
+func (d *Dictionary) WriteTo(w io.Writer) (int64, error) {
+	buf := append([]byte(Magic), 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(buf[len(Magic):], d.ID)
+	buf = huffman.AppendCodeTable(buf, d.Codes)
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// Read reads a dictionary written by WriteTo and checks that it is complete and matches its ID.
+func Read(r io.Reader) (*Dictionary, error) {
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(Magic)+4)
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("error reading dictionary header: %w", err)
+	}
+	if !bytes.HasPrefix(header, []byte(Magic)) {
+		return nil, fmt.Errorf("invalid dictionary magic")
+	}
+
+	codeTable, err := huffman.ReadCodeTable(br)
+	if err != nil {
+		return nil, err
+	}
+	if len(codeTable) != 256 {
+		return nil, fmt.Errorf("invalid dictionary: %d of 256 byte values have a code", len(codeTable))
+	}
+
+	codes := make(map[byte]string, len(codeTable))
+	for code, char := range codeTable {
+		if code == "" {
+			return nil, fmt.Errorf("invalid dictionary: empty code")
+		}
+		codes[char] = code
+	}
+	if len(codes) != 256 {
+		return nil, fmt.Errorf("invalid dictionary: duplicate byte value")
+	}
+
+	d := New(codes)
+	if id := binary.BigEndian.Uint32(header[len(Magic):]); id != d.ID {
+		return nil, fmt.Errorf("invalid dictionary: ID %08x does not match its table %08x", id, d.ID)
+	}
+	return d, nil
+}
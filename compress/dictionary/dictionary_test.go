@@ -0,0 +1,75 @@
+package dictionary
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTrain(t *testing.T) {
+	samples := [][]byte{
+		[]byte(`{"id":1,"name":"alice"}`),
+		[]byte(`{"id":2,"name":"bob"}`),
+	}
+	d := Train(samples)
+
+	if len(d.Codes) != 256 {
+		t.Fatalf("Expected a code for every byte value, got %d", len(d.Codes))
+	}
+	// Bytes from the samples get shorter codes than bytes that never occur
+	if len(d.Codes['"']) >= len(d.Codes[0xff]) {
+		t.Errorf("Expected %q to get a shorter code than 0xff, got %s and %s", '"', d.Codes['"'], d.Codes[0xff])
+	}
+	if d.ID != New(d.Codes).ID {
+		t.Error("Expected the ID to be derived from the code table")
+	}
+}
+
+func TestDictionaryRoundTrip(t *testing.T) {
+	d := Train([][]byte{[]byte("hello dictionary")})
+
+	var buf bytes.Buffer
+	if _, err := d.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(read, d) {
+		t.Errorf("Expected %v, got %v", d, read)
+	}
+	if read.ReverseLookup()[d.Codes['h']] != 'h' {
+		t.Error("Expected reverse lookup to map codes back to bytes")
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	var valid bytes.Buffer
+	Train(nil).WriteTo(&valid)
+
+	testCases := []struct {
+		name          string
+		contents      func() []byte
+		expectedError string
+	}{
+		{name: "Truncated", contents: func() []byte { return []byte("HZ") }, expectedError: "error reading dictionary header"},
+		{name: "Bad_magic", contents: func() []byte { return []byte("NOPE\x00\x00\x00\x00") }, expectedError: "invalid dictionary magic"},
+		{name: "Incomplete", contents: func() []byte { return []byte(Magic + "\x00\x00\x00\x00\x01a\x01\x00") }, expectedError: "1 of 256 byte values"},
+		{name: "Wrong_ID", contents: func() []byte {
+			b := append([]byte{}, valid.Bytes()...)
+			b[len(Magic)] ^= 0xff
+			return b
+		}, expectedError: "does not match"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Read(bytes.NewReader(tc.contents()))
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Farber98/cc-solutions/compress/container"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Sizes of the encryption header fields.
+const (
+	SaltSize  = 16
+	NonceSize = 12
+	KeySize   = 32
+	// HeaderSize is the size of magic, method, KDF, scrypt parameters, salt and nonce. The
+	// uvarint ciphertext size follows them.
+	HeaderSize = container.EncryptedHeaderSize
+)
+
+// kdfScrypt identifies scrypt as the key derivation function in the header.
+const kdfScrypt = 1
+
+// Limits on the scrypt parameters accepted from a header, so a crafted file cannot make key
+// derivation use gigabytes of memory. Key derivation needs 128*r*N bytes, which maxMemory
+// bounds, as the largest N and r allowed on their own would need 4 GiB together.
+const (
+	maxLogN   = 20
+	maxR      = 32
+	maxP      = 16
+	maxMemory = 256 << 20
+)
+
+// ErrAuthentication is returned when the authentication tag does not verify, because the
+// passphrase is wrong or the data was modified.
+var ErrAuthentication = errors.New("authentication failed: wrong passphrase or corrupt data")
+
+// Params are the scrypt cost parameters. N is 1<<LogN.
+type Params struct {
+	LogN byte
+	R    byte
+	P    byte
+}
+
+// DefaultParams are the scrypt parameters recommended for interactive use, about 32 MB of memory.
+var DefaultParams = Params{LogN: 15, R: 8, P: 1}
+
+// Encrypt derives a key from the passphrase with scrypt and a random salt and seals plaintext
+// with AES-256-GCM. The result is the header (magic, method E, KDF, parameters, salt, nonce and
+// the uvarint size of the rest) followed by the ciphertext and the tag. The header is
+// authenticated as additional data. The size lets another frame follow the encrypted one.
+func Encrypt(plaintext, passphrase []byte, params Params) ([]byte, error) {
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+
+	header := append([]byte(container.Magic), byte(container.MethodEncrypted), kdfScrypt|container.EncryptedSizedFlag, params.LogN, params.R, params.P)
+	random := make([]byte, SaltSize+NonceSize)
+	if _, err := io.ReadFull(rand.Reader, random); err != nil {
+		return nil, fmt.Errorf("error generating salt and nonce: %w", err)
+	}
+	header = append(header, random...)
+
+	aead, err := newAEAD(passphrase, random[:SaltSize], params)
+	if err != nil {
+		return nil, err
+	}
+	header = binary.AppendUvarint(header, uint64(len(plaintext)+aead.Overhead()))
+	return aead.Seal(header, random[SaltSize:], plaintext, header), nil
+}
+
+// Decrypt opens a frame produced by Encrypt. Nothing is returned unless the tag verifies. Frames
+// written before the ciphertext size was recorded are read to the end of data.
+func Decrypt(data, passphrase []byte) ([]byte, error) {
+	if !container.IsEncrypted(data) {
+		return nil, fmt.Errorf("not an encrypted frame")
+	}
+	if len(data) < HeaderSize {
+		return nil, fmt.Errorf("error reading encryption header: %w", io.ErrUnexpectedEOF)
+	}
+	if len(passphrase) == 0 {
+		return nil, fmt.Errorf("empty passphrase")
+	}
+
+	size, sized, err := container.EncryptedSize(data)
+	if err != nil {
+		return nil, err
+	}
+	headerSize := HeaderSize
+	if sized {
+		if size != uint64(len(data)) {
+			return nil, fmt.Errorf("invalid encrypted frame: expected %d bytes, got %d", size, len(data))
+		}
+		_, n := binary.Uvarint(data[HeaderSize:])
+		headerSize += n
+	}
+
+	header := data[:headerSize]
+	fields := header[len(container.Magic)+1 : HeaderSize]
+	if kdf := fields[0] &^ container.EncryptedSizedFlag; kdf != kdfScrypt {
+		return nil, fmt.Errorf("unknown key derivation function: %d", kdf)
+	}
+	params := Params{LogN: fields[1], R: fields[2], P: fields[3]}
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	salt := fields[4 : 4+SaltSize]
+	nonce := fields[4+SaltSize:]
+
+	aead, err := newAEAD(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, nonce, data[headerSize:], header)
+	if err != nil {
+		return nil, ErrAuthentication
+	}
+	return plaintext, nil
+}
+
+// newAEAD derives the key and returns the AES-GCM cipher.
+func newAEAD(passphrase, salt []byte, params Params) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, salt, 1<<params.LogN, int(params.R), int(params.P), KeySize)
+	if err != nil {
+		return nil, fmt.Errorf("error deriving key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// validate checks the parameters against the accepted limits.
+func (p Params) validate() error {
+	if p.LogN < 1 || p.LogN > maxLogN || p.R < 1 || p.R > maxR || p.P < 1 || p.P > maxP {
+		return fmt.Errorf("invalid scrypt parameters: N=2^%d r=%d p=%d", p.LogN, p.R, p.P)
+	}
+	if memory := p.memory(); memory > maxMemory {
+		return fmt.Errorf("invalid scrypt parameters: N=2^%d r=%d needs %d MB, more than %d MB", p.LogN, p.R, memory>>20, maxMemory>>20)
+	}
+	return nil
+}
+
+// memory returns the bytes of memory scrypt needs with the parameters, 128*r*N.
+func (p Params) memory() uint64 {
+	return 128 * uint64(p.R) << p.LogN
+}
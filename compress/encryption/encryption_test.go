@@ -0,0 +1,145 @@
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testParams keeps key derivation fast in tests.
+var testParams = Params{LogN: 4, R: 8, P: 1}
+
+func TestEncryptDecrypt(t *testing.T) {
+	testCases := []struct {
+		name      string
+		plaintext []byte
+	}{
+		{name: "Empty", plaintext: []byte{}},
+		{name: "Text", plaintext: []byte("partner export, do not share")},
+		{name: "Binary", plaintext: bytes.Repeat([]byte{0, 0xff, 7}, 1000)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encrypted, err := Encrypt(tc.plaintext, []byte("secret"), testParams)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(tc.plaintext) > 0 && bytes.Contains(encrypted, tc.plaintext) {
+				t.Error("Expected the plaintext not to appear in the output")
+			}
+
+			decrypted, err := Decrypt(encrypted, []byte("secret"))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !bytes.Equal(decrypted, tc.plaintext) {
+				t.Errorf("Expected %q, got %q", tc.plaintext, decrypted)
+			}
+		})
+	}
+}
+
+func TestEncryptUsesFreshSalt(t *testing.T) {
+	first, _ := Encrypt([]byte("same"), []byte("secret"), testParams)
+	second, _ := Encrypt([]byte("same"), []byte("secret"), testParams)
+	if bytes.Equal(first, second) {
+		t.Error("Expected different salt and nonce for every encryption")
+	}
+}
+
+func TestDecryptErrors(t *testing.T) {
+	encrypted, err := Encrypt([]byte("attack at dawn"), []byte("secret"), testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name          string
+		data          func() []byte
+		passphrase    string
+		expectedError error
+		errorContains string
+	}{
+		{name: "Wrong_passphrase", data: func() []byte { return encrypted }, passphrase: "guess", expectedError: ErrAuthentication},
+		{name: "Tampered_ciphertext", data: func() []byte { return flip(encrypted, len(encrypted)-20) }, passphrase: "secret", expectedError: ErrAuthentication},
+		{name: "Tampered_tag", data: func() []byte { return flip(encrypted, len(encrypted)-1) }, passphrase: "secret", expectedError: ErrAuthentication},
+		{name: "Tampered_salt", data: func() []byte { return flip(encrypted, HeaderSize-NonceSize-1) }, passphrase: "secret", expectedError: ErrAuthentication},
+		{name: "Truncated", data: func() []byte { return encrypted[:HeaderSize-1] }, passphrase: "secret", errorContains: "error reading encryption header"},
+		{name: "Huge_parameters", data: func() []byte {
+			b := append([]byte{}, encrypted...)
+			b[4] = 40
+			return b
+		}, passphrase: "secret", errorContains: "invalid scrypt parameters"},
+		{name: "Memory_over_limit", data: func() []byte {
+			// N and r are within their own limits, but need 4 GiB together
+			b := append([]byte{}, encrypted...)
+			b[4], b[5] = 20, 32
+			return b
+		}, passphrase: "secret", errorContains: "needs 4096 MB, more than 256 MB"},
+		{name: "Trailing_data", data: func() []byte { return append(append([]byte{}, encrypted...), 0) }, passphrase: "secret", errorContains: "invalid encrypted frame"},
+		{name: "Empty_passphrase", data: func() []byte { return encrypted }, passphrase: "", errorContains: "empty passphrase"},
+		{name: "Not_encrypted", data: func() []byte { return []byte("HZH\x00") }, passphrase: "secret", errorContains: "not an encrypted frame"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			decrypted, err := Decrypt(tc.data(), []byte(tc.passphrase))
+			if decrypted != nil {
+				t.Error("Expected no plaintext on failure")
+			}
+			if tc.expectedError != nil && !errors.Is(err, tc.expectedError) {
+				t.Errorf("Expected %v, got %v", tc.expectedError, err)
+			}
+			if tc.errorContains != "" && (err == nil || !strings.Contains(err.Error(), tc.errorContains)) {
+				t.Errorf("Expected error containing %q, got %v", tc.errorContains, err)
+			}
+		})
+	}
+}
+
+func TestParamsValidate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		params Params
+		valid  bool
+	}{
+		{name: "Default", params: DefaultParams, valid: true},
+		{name: "At_memory_limit", params: Params{LogN: 20, R: 2, P: 1}, valid: true},
+		{name: "Over_memory_limit", params: Params{LogN: 20, R: 3, P: 1}},
+		{name: "Large_r", params: Params{LogN: 14, R: 32, P: 1}, valid: true},
+		{name: "Zero_r", params: Params{LogN: 15, R: 0, P: 1}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.params.validate(); (err == nil) != tc.valid {
+				t.Errorf("Expected valid %v, got %v", tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestDecryptUnsized(t *testing.T) {
+	// Frames written before the ciphertext size was recorded run to the end of the data
+	header := append([]byte("HZE"), kdfScrypt, testParams.LogN, testParams.R, testParams.P)
+	header = append(header, make([]byte, SaltSize+NonceSize)...)
+	aead, err := newAEAD([]byte("secret"), header[HeaderSize-NonceSize-SaltSize:HeaderSize-NonceSize], testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted := aead.Seal(header, header[HeaderSize-NonceSize:], []byte("old frame"), header)
+
+	decrypted, err := Decrypt(encrypted, []byte("secret"))
+	if err != nil || string(decrypted) != "old frame" {
+		t.Errorf("Expected %q, got %q and error %v", "old frame", decrypted, err)
+	}
+}
+
+// flip returns a copy of data with the bits of the byte at i inverted.
+func flip(data []byte, i int) []byte {
+	b := append([]byte{}, data...)
+	b[i] ^= 0xff
+	return b
+}
@@ -21,8 +21,10 @@ type File interface {
 	WriteTextAfterHeader(byteText []byte, outputPath string) error
 	CreateTempFileWithData(data []byte) (string, func(), error)
 	ReadReverseLookupCodesTableFromHeader(filePath string) (map[string]byte, error)
+	ParseReverseLookupCodesTable(contents []byte) (map[string]byte, error)
 	CreateNewFile(fileName string) (*os.File, error)
 	ReadTextAfterHeader(filePath string) ([]byte, error)
+	ParseTextAfterHeader(contents []byte) ([]byte, error)
 }
 
 // DefaultFile implements the File interface with default file operations.
@@ -161,6 +163,11 @@ func (f *DefaultFile) ReadReverseLookupCodesTableFromHeader(filePath string) (ma
 		return nil, err
 	}
 
+	return f.ParseReverseLookupCodesTable(contents)
+}
+
+// ParseReverseLookupCodesTable parses the reversed codesTable from the header at the start of contents.
+func (f *DefaultFile) ParseReverseLookupCodesTable(contents []byte) (map[string]byte, error) {
 	// Split the contents by newline to get individual lines
 	lines := strings.Split(string(contents), "\n")
 
@@ -218,6 +225,11 @@ func (f *DefaultFile) ReadTextAfterHeader(filePath string) ([]byte, error) {
 		return nil, err
 	}
 
+	return f.ParseTextAfterHeader(contents)
+}
+
+// ParseTextAfterHeader returns the encoded content of contents after the header.
+func (f *DefaultFile) ParseTextAfterHeader(contents []byte) ([]byte, error) {
 	// Split the contents by newline to get individual lines
 	lines := strings.Split(string(contents), "\n")
 
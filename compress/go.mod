@@ -1,3 +1,5 @@
 module github.com/Farber98/cc-solutions/compress
 
 go 1.20
+
+require golang.org/x/crypto v0.17.0
@@ -0,0 +1,349 @@
+package httpencoding
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Content codings understood by the middleware. HTTP "deflate" is the zlib format (RFC 1950),
+// not a raw DEFLATE stream.
+const (
+	Gzip    = "gzip"
+	Deflate = "deflate"
+)
+
+// Config controls which responses are compressed.
+type Config struct {
+	// MinSize is the smallest body, in bytes, worth compressing. Smaller bodies are sent as is.
+	MinSize int
+	// ContentTypes lists the media types to compress. An entry ending in a slash, like "text/",
+	// matches every subtype. An empty list compresses every type.
+	ContentTypes []string
+	// Level is a compress/flate level, from flate.HuffmanOnly to flate.BestCompression.
+	Level int
+	// MaxBodySize is the largest decompressed request body, in bytes, a handler can read. Reading
+	// past it fails with an *http.MaxBytesError, so a small compressed body cannot expand into
+	// gigabytes. Zero means DefaultMaxBodySize and a negative size means no limit.
+	MaxBodySize int64
+}
+
+// DefaultMaxBodySize is the limit on decompressed request bodies when Config.MaxBodySize is zero.
+const DefaultMaxBodySize = 10 << 20
+
+// DefaultConfig compresses text like responses of at least 1 KiB with the default level.
+var DefaultConfig = Config{
+	MinSize: 1024,
+	ContentTypes: []string{
+		"text/",
+		"application/json",
+		"application/javascript",
+		"application/xml",
+		"image/svg+xml",
+	},
+	Level:       flate.DefaultCompression,
+	MaxBodySize: DefaultMaxBodySize,
+}
+
+// Handler wraps next so that responses are compressed with gzip or deflate when the request
+// allows it in Accept-Encoding, and request bodies sent with a gzip or deflate Content-Encoding
+// are decompressed before next reads them, up to the MaxBodySize of config. Requests with any
+// other encoding are rejected with 415 Unsupported Media Type. It panics if the level of config
+// is invalid.
+func Handler(next http.Handler, config Config) http.Handler {
+	if config.Level < flate.HuffmanOnly || config.Level > flate.BestCompression {
+		panic(fmt.Sprintf("httpencoding: invalid compression level: %d", config.Level))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := decodeRequest(w, r, config.maxBodySize()); err != nil {
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		}
+
+		// Caches must not serve a compressed response to a client that cannot decode it
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := Negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Range") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &responseWriter{ResponseWriter: w, config: &config, encoding: encoding}
+		defer cw.Close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// Negotiate returns the coding to use for an Accept-Encoding header value: gzip when it is
+// acceptable, then deflate, or "" when the response has to be sent as is.
+func Negotiate(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		qualities[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range []string{Gzip, Deflate} {
+		q, ok := qualities[encoding]
+		if !ok {
+			q, ok = qualities["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// maxBodySize returns the limit on decompressed request bodies, or a negative size for none.
+func (c *Config) maxBodySize() int64 {
+	if c.MaxBodySize == 0 {
+		return DefaultMaxBodySize
+	}
+	return c.MaxBodySize
+}
+
+// decodeRequest replaces the body of r with a decompressing reader when it has a Content-Encoding,
+// which fails once more than maxBodySize bytes are read unless maxBodySize is negative.
+func decodeRequest(w http.ResponseWriter, r *http.Request, maxBodySize int64) error {
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	var body io.ReadCloser
+	switch encoding {
+	case "", "identity":
+		return nil
+	case Gzip, "x-gzip":
+		body = &lazyReader{source: r.Body, open: func(src io.Reader) (io.Reader, error) { return gzip.NewReader(src) }}
+	case Deflate:
+		body = &lazyReader{source: r.Body, open: func(src io.Reader) (io.Reader, error) { return zlib.NewReader(src) }}
+	default:
+		return fmt.Errorf("unsupported content encoding: %s", encoding)
+	}
+
+	if maxBodySize >= 0 {
+		body = http.MaxBytesReader(w, body, maxBodySize)
+	}
+	r.Body = body
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+	return nil
+}
+
+// lazyReader opens the decompressor on the first read, so a malformed header is reported to
+// the handler as a read error instead of before it runs.
+type lazyReader struct {
+	source io.ReadCloser
+	open   func(io.Reader) (io.Reader, error)
+	reader io.Reader
+	err    error
+}
+
+// Read implements io.Reader.
+func (l *lazyReader) Read(p []byte) (int, error) {
+	if l.reader == nil && l.err == nil {
+		l.reader, l.err = l.open(l.source)
+		if l.err != nil {
+			l.err = fmt.Errorf("error decoding request body: %w", l.err)
+		}
+	}
+	if l.err != nil {
+		return 0, l.err
+	}
+	return l.reader.Read(p)
+}
+
+// Close implements io.Closer.
+func (l *lazyReader) Close() error {
+	return l.source.Close()
+}
+
+// responseWriter buffers the start of a response until it knows whether it is worth compressing:
+// when MinSize bytes were written, or when the handler flushes or returns.
+type responseWriter struct {
+	http.ResponseWriter
+	config   *Config
+	encoding string
+
+	status      int
+	wroteHeader bool
+	decided     bool
+	buf         []byte
+	encoder     io.WriteCloser
+}
+
+// WriteHeader implements http.ResponseWriter. The status is held back with the body.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	// Informational responses are sent right away and do not end the header
+	if status >= 100 && status < 200 && status != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+
+	if !bodyAllowed(status) || w.Header().Get("Content-Encoding") != "" {
+		w.start(false)
+	}
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(p)
+		}
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= w.config.MinSize && len(w.buf) > 0 {
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush implements http.Flusher. It ends buffering, so a streamed response is compressed only
+// when the data written so far is already large enough.
+func (w *responseWriter) Flush() {
+	if !w.decided {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		w.decide()
+	}
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		flusher.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker for handlers that take over the connection, like websockets.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Close sends whatever is still buffered and finishes the compressed stream.
+func (w *responseWriter) Close() error {
+	if !w.decided {
+		if !w.wroteHeader {
+			// The handler wrote nothing at all, let net/http send its default response
+			return nil
+		}
+		if err := w.decide(); err != nil {
+			return err
+		}
+	}
+	if w.encoder != nil {
+		return w.encoder.Close()
+	}
+	return nil
+}
+
+// decide compresses the buffered response if it is large enough and of an accepted type.
+func (w *responseWriter) decide() error {
+	header := w.Header()
+	if header.Get("Content-Type") == "" && len(w.buf) > 0 {
+		// Sniff the type now, net/http would otherwise sniff the compressed bytes
+		header.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+	compress := len(w.buf) > 0 && len(w.buf) >= w.config.MinSize &&
+		header.Get("Content-Encoding") == "" && w.config.matches(header.Get("Content-Type"))
+	return w.start(compress)
+}
+
+// start sends the header, switching the response to the negotiated coding when compress is
+// set, and writes out the buffered body.
+func (w *responseWriter) start(compress bool) error {
+	w.decided = true
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+		// Levels were validated by Handler, so the constructors cannot fail
+		if w.encoding == Gzip {
+			w.encoder, _ = gzip.NewWriterLevel(w.ResponseWriter, w.config.Level)
+		} else {
+			w.encoder, _ = zlib.NewWriterLevel(w.ResponseWriter, w.config.Level)
+		}
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(buf)
+	} else {
+		_, err = w.ResponseWriter.Write(buf)
+	}
+	return err
+}
+
+// matches reports whether responses of contentType are compressed.
+func (c *Config) matches(contentType string) bool {
+	if len(c.ContentTypes) == 0 {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, accepted := range c.ContentTypes {
+		accepted = strings.ToLower(accepted)
+		if strings.HasSuffix(accepted, "/") && strings.HasPrefix(mediaType, accepted) || mediaType == accepted {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyAllowed reports whether a response with status may have a body.
+func bodyAllowed(status int) bool {
+	return status != http.StatusNoContent && status != http.StatusNotModified &&
+		(status < 100 || status >= 200)
+}
@@ -0,0 +1,274 @@
+package httpencoding
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		acceptEncoding string
+		expected       string
+	}{
+		{name: "Empty", acceptEncoding: "", expected: ""},
+		{name: "Gzip", acceptEncoding: "gzip", expected: Gzip},
+		{name: "Deflate", acceptEncoding: "deflate", expected: Deflate},
+		{name: "Prefers_gzip", acceptEncoding: "deflate, gzip", expected: Gzip},
+		{name: "Quality", acceptEncoding: "gzip;q=0.5, deflate;q=0.8", expected: Deflate},
+		{name: "Refused", acceptEncoding: "gzip;q=0", expected: ""},
+		{name: "Wildcard", acceptEncoding: "br, *", expected: Gzip},
+		{name: "Wildcard_except_gzip", acceptEncoding: "*, gzip;q=0", expected: Deflate},
+		{name: "Unsupported", acceptEncoding: "br, zstd", expected: ""},
+		{name: "Case_and_spaces", acceptEncoding: " GZIP ; q=1.0 ", expected: Gzip},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Negotiate(tc.acceptEncoding); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestHandler_Responses(t *testing.T) {
+	large := strings.Repeat("compress me please ", 200)
+
+	testCases := []struct {
+		name             string
+		method           string
+		acceptEncoding   string
+		contentType      string
+		contentEncoding  string
+		status           int
+		body             string
+		expectedEncoding string
+	}{
+		{name: "Gzip", acceptEncoding: "gzip", contentType: "text/plain", body: large, expectedEncoding: Gzip},
+		{name: "Deflate", acceptEncoding: "deflate", contentType: "application/json", body: large, expectedEncoding: Deflate},
+		{name: "Not_accepted", acceptEncoding: "", contentType: "text/plain", body: large},
+		{name: "Below_threshold", acceptEncoding: "gzip", contentType: "text/plain", body: "small"},
+		{name: "Filtered_type", acceptEncoding: "gzip", contentType: "image/png", body: large},
+		{name: "Sniffed_type", acceptEncoding: "gzip", body: large, expectedEncoding: Gzip},
+		{name: "Type_with_parameters", acceptEncoding: "gzip", contentType: "text/html; charset=utf-8", body: large, expectedEncoding: Gzip},
+		{name: "Already_encoded", acceptEncoding: "gzip", contentType: "text/plain", contentEncoding: "br", body: large, expectedEncoding: "br"},
+		{name: "Error_status", acceptEncoding: "gzip", contentType: "text/plain", status: http.StatusTooManyRequests, body: large, expectedEncoding: Gzip},
+		{name: "No_content", acceptEncoding: "gzip", status: http.StatusNoContent},
+		{name: "Head", method: http.MethodHead, acceptEncoding: "gzip", contentType: "text/plain"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.contentType != "" {
+					w.Header().Set("Content-Type", tc.contentType)
+				}
+				if tc.contentEncoding != "" {
+					w.Header().Set("Content-Encoding", tc.contentEncoding)
+				}
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+				}
+				// Write in pieces so the threshold is crossed in the middle of a write
+				for i := 0; i < len(tc.body); i += 100 {
+					end := i + 100
+					if end > len(tc.body) {
+						end = len(tc.body)
+					}
+					w.Write([]byte(tc.body[i:end]))
+				}
+			}), DefaultConfig)
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/", nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			expectedStatus := tc.status
+			if expectedStatus == 0 {
+				expectedStatus = http.StatusOK
+			}
+			if rec.Code != expectedStatus {
+				t.Errorf("Expected status %d, got %d", expectedStatus, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Encoding"); got != tc.expectedEncoding {
+				t.Fatalf("Expected Content-Encoding %q, got %q", tc.expectedEncoding, got)
+			}
+			if got := rec.Header().Get("Vary"); got != "Accept-Encoding" {
+				t.Errorf("Expected Vary: Accept-Encoding, got %q", got)
+			}
+
+			body := rec.Body.Bytes()
+			switch tc.expectedEncoding {
+			case Gzip:
+				body = decode(t, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }, body)
+			case Deflate:
+				body = decode(t, func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) }, body)
+			}
+			if string(body) != tc.body {
+				t.Errorf("Expected body of %d bytes, got %d", len(tc.body), len(body))
+			}
+		})
+	}
+}
+
+func TestHandler_Flush(t *testing.T) {
+	handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("data: first\n\n"))
+		w.(http.Flusher).Flush()
+		w.Write([]byte(strings.Repeat("data: more\n\n", 200)))
+	}), Config{MinSize: 1024, Level: gzip.BestSpeed})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	// The flush came before the threshold, so the stream is sent as is
+	if got := rec.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("Expected no Content-Encoding after an early flush, got %q", got)
+	}
+	if !rec.Flushed {
+		t.Errorf("Expected the flush to reach the underlying writer")
+	}
+	if !strings.HasPrefix(rec.Body.String(), "data: first") {
+		t.Errorf("Expected the flushed data first, got %q", rec.Body.String()[:20])
+	}
+}
+
+func TestHandler_Requests(t *testing.T) {
+	payload := []byte(`{"key":"value"}`)
+
+	var gzipped, deflated bytes.Buffer
+	gw := gzip.NewWriter(&gzipped)
+	gw.Write(payload)
+	gw.Close()
+	zw := zlib.NewWriter(&deflated)
+	zw.Write(payload)
+	zw.Close()
+
+	testCases := []struct {
+		name            string
+		contentEncoding string
+		body            []byte
+		expectedStatus  int
+		expectedBody    string
+	}{
+		{name: "Identity", body: payload, expectedStatus: http.StatusOK, expectedBody: string(payload)},
+		{name: "Gzip", contentEncoding: "gzip", body: gzipped.Bytes(), expectedStatus: http.StatusOK, expectedBody: string(payload)},
+		{name: "Deflate", contentEncoding: "deflate", body: deflated.Bytes(), expectedStatus: http.StatusOK, expectedBody: string(payload)},
+		{name: "Unsupported", contentEncoding: "br", body: payload, expectedStatus: http.StatusUnsupportedMediaType},
+		{name: "Corrupt", contentEncoding: "gzip", body: payload, expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Encoding") != "" {
+					t.Errorf("Expected Content-Encoding to be removed")
+				}
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				w.Write(body)
+			}), DefaultConfig)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tc.body))
+			if tc.contentEncoding != "" {
+				req.Header.Set("Content-Encoding", tc.contentEncoding)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tc.expectedStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tc.expectedStatus, rec.Code, rec.Body.String())
+			}
+			if tc.expectedStatus == http.StatusOK && rec.Body.String() != tc.expectedBody {
+				t.Errorf("Expected body %q, got %q", tc.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_MaxBodySize(t *testing.T) {
+	var bomb bytes.Buffer
+	gw := gzip.NewWriter(&bomb)
+	gw.Write(make([]byte, 2<<20))
+	gw.Close()
+
+	testCases := []struct {
+		name           string
+		maxBodySize    int64
+		expectedStatus int
+	}{
+		{name: "Over_the_limit", maxBodySize: 1 << 20, expectedStatus: http.StatusRequestEntityTooLarge},
+		{name: "Default_limit", maxBodySize: 0, expectedStatus: http.StatusOK},
+		{name: "No_limit", maxBodySize: -1, expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := DefaultConfig
+			config.MaxBodySize = tc.maxBodySize
+			handler := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+					return
+				}
+				if err != nil || len(body) != 2<<20 {
+					t.Errorf("Expected the whole body, got %d bytes (%v)", len(body), err)
+				}
+			}), config)
+
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(bomb.Bytes()))
+			req.Header.Set("Content-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tc.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tc.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestHandler_InvalidLevel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected a panic for an invalid level")
+		}
+	}()
+	Handler(http.NotFoundHandler(), Config{Level: 42})
+}
+
+// decode decompresses body with the reader returned by open.
+func decode(t *testing.T, open func(io.Reader) (io.Reader, error), body []byte) []byte {
+	t.Helper()
+	r, err := open(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Expected a valid compressed body, got %v", err)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Expected a valid compressed body, got %v", err)
+	}
+	return decoded
+}
@@ -0,0 +1,209 @@
+// Package huff reads and writes compressed streams in the frame format of the compress command,
+// following the conventions of the standard library compress packages.
+package huff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/dictionary"
+	"github.com/Farber98/cc-solutions/compress/internal/framing"
+	"github.com/Farber98/cc-solutions/compress/preset"
+)
+
+// Compression levels, as given to WithLevel.
+const (
+	// DefaultCompression codes the data with plain static Huffman, like compress without flags.
+	DefaultCompression = 0
+	BestSpeed          = preset.MinLevel
+	BestCompression    = preset.MaxLevel
+)
+
+// DefaultFrameSize is the amount of data a Writer codes into one frame, the largest BWT block
+// of the levels.
+const DefaultFrameSize = 4 << 20
+
+// ErrHeader is returned when reading data that does not start with a frame.
+var ErrHeader = errors.New("invalid header: not a compressed stream")
+
+// Progress reports how much of a stream a Writer or a Reader has processed.
+type Progress struct {
+	// In is the number of bytes consumed: original bytes for a Writer, compressed bytes for a Reader.
+	In int64
+	// Out is the number of bytes produced.
+	Out int64
+}
+
+// Option configures a Writer or a Reader.
+type Option func(*config)
+
+// config holds the settings chosen with options.
+type config struct {
+	level      int
+	auto       bool
+	frameSize  int
+	dict       *dictionary.Dictionary
+	passphrase []byte
+	ctx        context.Context
+	progress   func(Progress)
+}
+
+// WithLevel selects a compression level from BestSpeed to BestCompression, with the coder and
+// stages of compress -1 to -9. Readers ignore it.
+func WithLevel(level int) Option {
+	return func(c *config) { c.level = level }
+}
+
+// WithAuto picks the coder and stages of every frame from a sample of its data, like
+// compress -auto. Readers ignore it.
+func WithAuto() Option {
+	return func(c *config) { c.auto = true }
+}
+
+// WithFrameSize sets the amount of data a Writer codes into one frame, DefaultFrameSize when
+// not given. Larger frames compress better and need more memory to write and read. Readers
+// ignore it.
+func WithFrameSize(size int) Option {
+	return func(c *config) { c.frameSize = size }
+}
+
+// WithDictionary codes frames with the table of a dictionary built by train instead of storing
+// one. Readers need the same dictionary. It cannot be combined with a level or WithAuto.
+func WithDictionary(d *dictionary.Dictionary) Option {
+	return func(c *config) { c.dict = d }
+}
+
+// WithPassphrase encrypts the stream with a key derived from the passphrase, like
+// compress -encrypt, and lets readers decrypt it. An encrypted stream is a single frame
+// authenticated as a whole, so a Writer holds all the data until Close and a Reader reads all of
+// it before returning any: memory grows with the stream instead of staying bounded.
+func WithPassphrase(passphrase []byte) Option {
+	return func(c *config) { c.passphrase = passphrase }
+}
+
+// WithContext stops a Writer or a Reader once ctx is done: the frame being coded is finished,
+// and every later call fails with the error of ctx. Whatever was written so far ends with a
+// complete frame, but the stream is missing data, so callers should discard it.
+func WithContext(ctx context.Context) Option {
+	return func(c *config) { c.ctx = ctx }
+}
+
+// WithProgress calls report after every frame a Writer writes or a Reader decodes, with the
+// totals so far.
+func WithProgress(report func(Progress)) Option {
+	return func(c *config) { c.progress = report }
+}
+
+// newConfig applies the options over the defaults and validates the result.
+func newConfig(opts []Option) (config, error) {
+	c := config{frameSize: DefaultFrameSize, ctx: context.Background()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if c.level != DefaultCompression && (c.level < BestSpeed || c.level > BestCompression) {
+		return config{}, fmt.Errorf("invalid level: %d", c.level)
+	}
+	if c.level != DefaultCompression && c.auto {
+		return config{}, fmt.Errorf("a level and auto cannot be used together")
+	}
+	if c.dict != nil && (c.level != DefaultCompression || c.auto) {
+		return config{}, fmt.Errorf("a dictionary cannot be used with a level or auto")
+	}
+	if c.ctx == nil {
+		return config{}, fmt.Errorf("nil context")
+	}
+	if c.frameSize <= 0 {
+		return config{}, fmt.Errorf("invalid frame size: %d", c.frameSize)
+	}
+	return c, nil
+}
+
+// Compress returns data compressed with the options.
+func Compress(data []byte, opts ...Option) ([]byte, error) {
+	var buffer bytes.Buffer
+	w, err := NewWriter(&buffer, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decompress returns the original data of a compressed stream.
+func Decompress(data []byte, opts ...Option) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(data), opts...)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// report calls the progress callback, when there is one.
+func (c *config) report(progress Progress) {
+	if c.progress != nil {
+		c.progress(progress)
+	}
+}
+
+// encodeFrame codes data into a sized frame with the preset of the config.
+func encodeFrame(c *config, data []byte) ([]byte, error) {
+	chosen := preset.Preset{Coder: "huffman"}
+	switch {
+	case c.auto:
+		chosen = preset.Auto(data)
+	case c.level != DefaultCompression:
+		chosen, _ = preset.Level(c.level)
+	}
+
+	method, compressor := newCompressor(chosen, c.dict)
+	return framing.Encode(method, compressor, chosen.Stages, data)
+}
+
+// newCompressor returns the frame method and compressor of a preset. Presets only use the
+// huffman, adaptive and range coders.
+func newCompressor(p preset.Preset, dict *dictionary.Dictionary) (container.Method, compress.Compressor) {
+	switch {
+	case dict != nil:
+		return container.MethodDictionary, &compress.DictionaryCompressor{Dictionary: dict}
+	case p.Coder == "range":
+		return container.MethodRange, &compress.RangeCompressor{Context: p.Context}
+	case p.Coder == "adaptive":
+		return container.MethodAdaptive, &compress.AdaptiveCompressor{}
+	default:
+		return container.MethodHuffman, &compress.HuffmanCompressor{}
+	}
+}
+
+// decodeFrame decodes an unencrypted frame and verifies it against its length and checksum.
+func decodeFrame(c *config, frame []byte) ([]byte, error) {
+	return framing.Decode(frame, func(method container.Method) (compress.LimitedDecompressor, error) {
+		return newDecompressor(method, c.dict)
+	})
+}
+
+// newDecompressor returns the decompressor for a frame method. Delta frames need the reference
+// file and can only be read with the decompress command.
+func newDecompressor(method container.Method, dict *dictionary.Dictionary) (compress.LimitedDecompressor, error) {
+	if method != container.MethodDictionary {
+		return framing.NewDecompressor(method)
+	}
+	if dict == nil {
+		return nil, fmt.Errorf("data was compressed with a dictionary, use WithDictionary")
+	}
+	return &compress.DictionaryDecompressor{Dictionary: dict}, nil
+}
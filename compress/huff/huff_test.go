@@ -0,0 +1,330 @@
+package huff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/dictionary"
+	"github.com/Farber98/cc-solutions/compress/encryption"
+)
+
+// logLines returns n numbered log lines.
+func logLines(n int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "2024-01-01T00:00:%02d line %d status=%d\n", i%60, i, 200+i%3)
+	}
+	return buf.Bytes()
+}
+
+func TestRoundTrip(t *testing.T) {
+	source := logLines(2000)
+	dict := dictionary.Train([][]byte{logLines(10)})
+
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "Default"},
+		{name: "BestSpeed", opts: []Option{WithLevel(BestSpeed)}},
+		{name: "Level_6", opts: []Option{WithLevel(6)}},
+		{name: "BestCompression", opts: []Option{WithLevel(BestCompression)}},
+		{name: "Auto", opts: []Option{WithAuto()}},
+		{name: "Dictionary", opts: []Option{WithDictionary(dict)}},
+		{name: "Small_frames", opts: []Option{WithLevel(3), WithFrameSize(10000)}},
+		{name: "Passphrase", opts: []Option{WithPassphrase([]byte("secret")), WithFrameSize(10000)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			compressed, err := Compress(source, tc.opts...)
+			if err != nil {
+				t.Fatalf("Compress() error: %v", err)
+			}
+			if len(compressed) >= len(source) {
+				t.Errorf("Expected fewer than %d bytes, got %d", len(source), len(compressed))
+			}
+
+			decompressed, err := Decompress(compressed, tc.opts...)
+			if err != nil {
+				t.Fatalf("Decompress() error: %v", err)
+			}
+			if !bytes.Equal(decompressed, source) {
+				t.Errorf("Expected %d bytes back, got %d", len(source), len(decompressed))
+			}
+		})
+	}
+}
+
+func TestWriter_Frames(t *testing.T) {
+	var buffer bytes.Buffer
+	w, err := NewWriter(&buffer, WithFrameSize(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Writes smaller and larger than a frame
+	source := logLines(50)
+	for i := 0; i < len(source); i += 70 {
+		end := i + 70
+		if end > len(source) {
+			end = len(source)
+		}
+		if _, err := w.Write(source[i:end]); err != nil {
+			t.Fatalf("Write() error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("Expected a second Close() to do nothing, got %v", err)
+	}
+	if _, err := w.Write([]byte("late")); err == nil {
+		t.Errorf("Expected Write() after Close() to fail")
+	}
+
+	// Every frame holds exactly the frame size, except the last
+	frames := 0
+	for data := buffer.Bytes(); len(data) > 0; frames++ {
+		size, err := container.FrameSize(data)
+		if err != nil {
+			t.Fatalf("FrameSize() error: %v", err)
+		}
+		header, err := container.ReadHeader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("ReadHeader() error: %v", err)
+		}
+		if len(data) > size && header.Length != 100 {
+			t.Errorf("Expected frames of 100 bytes, got %d", header.Length)
+		}
+		data = data[size:]
+	}
+	if expected := (len(source) + 99) / 100; frames != expected {
+		t.Errorf("Expected %d frames, got %d", expected, frames)
+	}
+
+	decompressed, err := Decompress(buffer.Bytes())
+	if err != nil || !bytes.Equal(decompressed, source) {
+		t.Errorf("Expected the source back, got %d bytes and error %v", len(decompressed), err)
+	}
+}
+
+func TestWriter_Flush(t *testing.T) {
+	var buffer bytes.Buffer
+	w, _ := NewWriter(&buffer)
+	w.Write([]byte("first message\n"))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error: %v", err)
+	}
+
+	// Everything written before the flush can be read while the writer is still open
+	r, err := NewReader(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		t.Fatalf("NewReader() error: %v", err)
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil || string(decoded) != "first message\n" {
+		t.Errorf("Expected the flushed message, got %q and error %v", decoded, err)
+	}
+
+	w.Write([]byte("second message\n"))
+	w.Close()
+	decoded, err = Decompress(buffer.Bytes())
+	if err != nil || string(decoded) != "first message\nsecond message\n" {
+		t.Errorf("Expected both messages, got %q and error %v", decoded, err)
+	}
+}
+
+func TestWriter_Empty(t *testing.T) {
+	var buffer bytes.Buffer
+	w, _ := NewWriter(&buffer)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	decoded, err := Decompress(buffer.Bytes())
+	if err != nil || len(decoded) != 0 {
+		t.Errorf("Expected an empty stream to decode to nothing, got %q and error %v", decoded, err)
+	}
+}
+
+func TestWriter_Reset(t *testing.T) {
+	var first, second bytes.Buffer
+	w, _ := NewWriter(&first, WithLevel(BestSpeed))
+	w.Write([]byte("first"))
+	w.Close()
+
+	w.Reset(&second)
+	w.Write([]byte("second"))
+	w.Close()
+
+	for expected, buffer := range map[string]*bytes.Buffer{"first": &first, "second": &second} {
+		decoded, err := Decompress(buffer.Bytes())
+		if err != nil || string(decoded) != expected {
+			t.Errorf("Expected %q, got %q and error %v", expected, decoded, err)
+		}
+	}
+}
+
+func TestProgress(t *testing.T) {
+	source := logLines(1000)
+	var writes []Progress
+	compressed, err := Compress(source, WithFrameSize(10000), WithProgress(func(p Progress) { writes = append(writes, p) }))
+	if err != nil {
+		t.Fatalf("Compress() error: %v", err)
+	}
+	if expected := (len(source) + 9999) / 10000; len(writes) != expected {
+		t.Fatalf("Expected a report for each of the %d frames, got %d", expected, len(writes))
+	}
+	if last := writes[len(writes)-1]; last != (Progress{In: int64(len(source)), Out: int64(len(compressed))}) {
+		t.Errorf("Expected the last report to hold the totals, got %+v", last)
+	}
+
+	var reads []Progress
+	if _, err := Decompress(compressed, WithProgress(func(p Progress) { reads = append(reads, p) })); err != nil {
+		t.Fatalf("Decompress() error: %v", err)
+	}
+	if len(reads) != len(writes) {
+		t.Fatalf("Expected %d reports, got %d", len(writes), len(reads))
+	}
+	for i := range reads {
+		// A reader consumes what the writer produced
+		if reads[i].In != writes[i].Out || reads[i].Out != writes[i].In {
+			t.Errorf("Expected report %d to mirror %+v, got %+v", i, writes[i], reads[i])
+		}
+	}
+}
+
+func TestContext_Canceled(t *testing.T) {
+	source := logLines(1000)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Cancel once the first frame is written
+	var buffer bytes.Buffer
+	w, _ := NewWriter(&buffer, WithFrameSize(10000), WithContext(ctx), WithProgress(func(Progress) { cancel() }))
+	if _, err := w.Write(source); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Write() to stop with %v, got %v", context.Canceled, err)
+	}
+	if err := w.Close(); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Close() to fail with %v, got %v", context.Canceled, err)
+	}
+	if size, err := container.FrameSize(buffer.Bytes()); err != nil || size != buffer.Len() {
+		t.Errorf("Expected exactly one complete frame, got %d of %d bytes and error %v", size, buffer.Len(), err)
+	}
+
+	compressed, _ := Compress(source, WithFrameSize(10000))
+	ctx, cancel = context.WithCancel(context.Background())
+	r, _ := NewReader(bytes.NewReader(compressed), WithContext(ctx), WithProgress(func(Progress) { cancel() }))
+	decoded, err := io.ReadAll(r)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected Read() to stop with %v, got %v", context.Canceled, err)
+	}
+	if len(decoded) != 10000 {
+		t.Errorf("Expected the first frame of 10000 bytes, got %d", len(decoded))
+	}
+}
+
+func TestOptions_Invalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		opts          []Option
+		expectedError string
+	}{
+		{name: "Level_too_low", opts: []Option{WithLevel(-1)}, expectedError: "invalid level: -1"},
+		{name: "Level_too_high", opts: []Option{WithLevel(10)}, expectedError: "invalid level: 10"},
+		{name: "Level_and_auto", opts: []Option{WithLevel(5), WithAuto()}, expectedError: "cannot be used together"},
+		{name: "Dictionary_and_level", opts: []Option{WithDictionary(dictionary.Train(nil)), WithLevel(1)}, expectedError: "dictionary cannot be used"},
+		{name: "Frame_size", opts: []Option{WithFrameSize(0)}, expectedError: "invalid frame size: 0"},
+		{name: "Nil_context", opts: []Option{WithContext(nil)}, expectedError: "nil context"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewWriter(io.Discard, tc.opts...); err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestReader_Errors(t *testing.T) {
+	source := logLines(100)
+	first, _ := Compress(source)
+	second, _ := Compress(source)
+	encrypted, _ := Compress(source, WithPassphrase([]byte("secret")))
+	dict := dictionary.Train([][]byte{source})
+	withDict, _ := Compress(source, WithDictionary(dict))
+
+	corrupt := append([]byte{}, first...)
+	corrupt[len(corrupt)-1] ^= 0xFF
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		opts          []Option
+		expectedErr   error
+		expectedError string
+	}{
+		{name: "Not_compressed", data: source, expectedErr: ErrHeader},
+		{name: "Empty", data: nil, expectedErr: ErrHeader},
+		{name: "Corrupt", data: corrupt, expectedErr: container.ErrChecksum},
+		{name: "Trailing_garbage", data: append(append([]byte{}, first...), "garbage"...), expectedError: "unexpected data after the last frame"},
+		{name: "Truncated", data: append(append([]byte{}, first...), second[:len(second)-5]...), expectedError: fmt.Sprintf("frame at byte %d: truncated frame", len(first))},
+		{name: "Encrypted_without_passphrase", data: encrypted, expectedErr: container.ErrEncrypted},
+		{name: "Wrong_passphrase", data: encrypted, opts: []Option{WithPassphrase([]byte("wrong"))}, expectedErr: encryption.ErrAuthentication},
+		{name: "Dictionary_missing", data: withDict, expectedError: "use WithDictionary"},
+		{name: "Wrong_dictionary", data: withDict, opts: []Option{WithDictionary(dictionary.Train(nil))}, expectedErr: dictionary.ErrMismatch},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decompress(tc.data, tc.opts...)
+			if tc.expectedErr != nil && !errors.Is(err, tc.expectedErr) {
+				t.Errorf("Expected error %v, got %v", tc.expectedErr, err)
+			}
+			if tc.expectedError != "" && (err == nil || !strings.Contains(err.Error(), tc.expectedError)) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestReader_Unsized(t *testing.T) {
+	// Frames written by older versions of the compress command run to the end of the stream
+	frame, _ := Compress([]byte("old frame"))
+	reader := bytes.NewReader(frame)
+	header, _ := container.ReadHeader(reader)
+	header.Sized = false
+
+	var unsized bytes.Buffer
+	container.WriteHeader(&unsized, header)
+	reader.WriteTo(&unsized)
+
+	decoded, err := Decompress(unsized.Bytes())
+	if err != nil || string(decoded) != "old frame" {
+		t.Errorf("Expected %q, got %q and error %v", "old frame", decoded, err)
+	}
+}
+
+func TestReader_EncryptedConcatenation(t *testing.T) {
+	// Encrypted streams record their size, so they can be followed by other streams
+	first, err := Compress([]byte("first "), WithPassphrase([]byte("secret")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := Compress([]byte("second"))
+	third, _ := Compress([]byte(" third"), WithPassphrase([]byte("secret")))
+
+	joined := append(append(first, second...), third...)
+	decoded, err := Decompress(joined, WithPassphrase([]byte("secret")))
+	if err != nil || string(decoded) != "first second third" {
+		t.Errorf("Expected %q, got %q and error %v", "first second third", decoded, err)
+	}
+}
@@ -0,0 +1,162 @@
+package huff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/encryption"
+)
+
+// Reader is an io.Reader that decompresses a stream of concatenated frames, written by a Writer
+// or the compress command. Every frame is verified against its length and checksum before any
+// of its data is returned. An encrypted frame is read whole before it is decrypted.
+type Reader struct {
+	r        *bufio.Reader
+	config   config
+	decoded  []byte
+	progress Progress
+	err      error
+}
+
+// NewReader returns a Reader that decompresses r. It fails with ErrHeader when r does not start
+// with a frame. The Reader may read more data than necessary from r.
+func NewReader(r io.Reader, opts ...Option) (*Reader, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	z := &Reader{config: c}
+	if err := z.Reset(r); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// Reset discards the state of the Reader and makes it read from r with the same options.
+func (z *Reader) Reset(r io.Reader) error {
+	*z = Reader{r: bufio.NewReader(r), config: z.config}
+	if magic, _ := z.r.Peek(len(container.Magic)); !container.IsFramed(magic) {
+		z.err = ErrHeader
+		return ErrHeader
+	}
+	return nil
+}
+
+// Read reads decompressed data into p, decoding the next frame when the current one is used up.
+func (z *Reader) Read(p []byte) (int, error) {
+	for len(z.decoded) == 0 {
+		if z.err != nil {
+			return 0, z.err
+		}
+		z.decoded, z.err = z.readFrame()
+	}
+	n := copy(p, z.decoded)
+	z.decoded = z.decoded[n:]
+	return n, nil
+}
+
+// Close does not close the underlying reader. It returns the error that stopped reading, if any.
+func (z *Reader) Close() error {
+	if z.err == io.EOF {
+		return nil
+	}
+	return z.err
+}
+
+// readFrame reads and decodes the next frame. It returns io.EOF at the end of the stream.
+func (z *Reader) readFrame() ([]byte, error) {
+	if err := z.config.ctx.Err(); err != nil {
+		return nil, err
+	}
+	prefix, _ := z.r.Peek(len(container.Magic) + 1)
+	if len(prefix) == 0 {
+		return nil, io.EOF
+	}
+	if !container.IsFramed(prefix) {
+		return nil, fmt.Errorf("unexpected data after the last frame at byte %d", z.progress.In)
+	}
+
+	frame, err := z.readMember()
+	var decoded []byte
+	if err == nil {
+		decoded, err = z.decodeMember(frame)
+	}
+	if err != nil && z.progress.In > 0 {
+		return nil, fmt.Errorf("frame at byte %d: %w", z.progress.In, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	z.progress.In += int64(len(frame))
+	z.progress.Out += int64(len(decoded))
+	z.config.report(z.progress)
+	return decoded, nil
+}
+
+// readMember reads the frame at the start of the stream. Unsized frames, and encrypted frames
+// written before their size was recorded, run to the end of the stream.
+func (z *Reader) readMember() ([]byte, error) {
+	if prefix, _ := z.r.Peek(len(container.Magic) + 1); container.IsEncrypted(prefix) {
+		return z.readEncrypted()
+	}
+
+	var frame bytes.Buffer
+	header, err := container.ReadHeader(io.TeeReader(z.r, &frame))
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+	if !header.Sized {
+		_, err := frame.ReadFrom(z.r)
+		return frame.Bytes(), err
+	}
+	if header.PayloadSize > math.MaxInt64 {
+		return nil, fmt.Errorf("invalid payload size: %d", header.PayloadSize)
+	}
+	if n, err := io.CopyN(&frame, z.r, int64(header.PayloadSize)); err == io.EOF {
+		return nil, fmt.Errorf("truncated frame: expected %d payload bytes, got %d", header.PayloadSize, n)
+	} else if err != nil {
+		return nil, err
+	}
+	return frame.Bytes(), nil
+}
+
+// readEncrypted reads the encrypted frame at the start of the stream.
+func (z *Reader) readEncrypted() ([]byte, error) {
+	// The header and the ciphertext size are read again with the rest of the frame
+	header, _ := z.r.Peek(container.EncryptedHeaderSize + binary.MaxVarintLen64)
+	size, sized, err := container.EncryptedSize(header)
+	if err != nil {
+		return nil, err
+	}
+	if !sized {
+		return io.ReadAll(z.r)
+	}
+
+	var frame bytes.Buffer
+	if n, err := io.CopyN(&frame, z.r, int64(size)); err == io.EOF {
+		return nil, fmt.Errorf("truncated frame: expected %d encrypted bytes, got %d", size, n)
+	} else if err != nil {
+		return nil, err
+	}
+	return frame.Bytes(), nil
+}
+
+// decodeMember decodes a frame, decrypting it first when it is encrypted.
+func (z *Reader) decodeMember(frame []byte) ([]byte, error) {
+	if !container.IsEncrypted(frame) {
+		return decodeFrame(&z.config, frame)
+	}
+	if len(z.config.passphrase) == 0 {
+		return nil, fmt.Errorf("%w, use WithPassphrase", container.ErrEncrypted)
+	}
+	decrypted, err := encryption.Decrypt(frame, z.config.passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return decodeFrame(&z.config, decrypted)
+}
@@ -0,0 +1,126 @@
+package huff
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/Farber98/cc-solutions/compress/encryption"
+)
+
+// Writer is an io.WriteCloser that compresses the data written to it. Data is coded in frames of
+// the frame size, concatenated like gzip members, so memory stays bounded for long streams,
+// unless the stream is encrypted, see WithPassphrase.
+// Writes to a Writer are buffered and may not be written to the underlying writer until Flush
+// or Close is called.
+type Writer struct {
+	w        io.Writer
+	config   config
+	buf      []byte
+	written  bool
+	closed   bool
+	progress Progress
+	err      error
+}
+
+// NewWriter returns a Writer that writes the compressed data to w. It fails on invalid options.
+func NewWriter(w io.Writer, opts ...Option) (*Writer, error) {
+	c, err := newConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{w: w, config: c}, nil
+}
+
+// Reset discards the state of the Writer and makes it write to w with the same options.
+func (z *Writer) Reset(w io.Writer) {
+	*z = Writer{w: w, config: z.config, buf: z.buf[:0]}
+}
+
+// Write buffers p and writes a frame every time the buffer reaches the frame size.
+func (z *Writer) Write(p []byte) (int, error) {
+	if z.err != nil {
+		return 0, z.err
+	}
+	if z.closed {
+		return 0, fmt.Errorf("write to a closed writer")
+	}
+
+	z.buf = append(z.buf, p...)
+	if z.encrypted() {
+		// An encrypted frame runs to the end of the stream, so it is written once by Close
+		return len(p), nil
+	}
+	for len(z.buf) >= z.config.frameSize {
+		if err := z.writeFrame(z.buf[:z.config.frameSize]); err != nil {
+			return 0, err
+		}
+		z.buf = z.buf[:copy(z.buf, z.buf[z.config.frameSize:])]
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered data as a frame, so a reader can decode everything written so far.
+// Frames have a fixed cost, so flushing often hurts the compression ratio. An encrypted stream
+// is a single frame, and Flush does nothing for it.
+func (z *Writer) Flush() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.closed || z.encrypted() || len(z.buf) == 0 {
+		return nil
+	}
+	if err := z.writeFrame(z.buf); err != nil {
+		return err
+	}
+	z.buf = z.buf[:0]
+	return nil
+}
+
+// Close writes the buffered data as the last frame. An empty stream is written as an empty
+// frame, so it still decodes. It does not close the underlying writer.
+func (z *Writer) Close() error {
+	if z.err != nil {
+		return z.err
+	}
+	if z.closed {
+		return nil
+	}
+	if len(z.buf) > 0 || !z.written {
+		if err := z.writeFrame(z.buf); err != nil {
+			return err
+		}
+	}
+	z.buf = z.buf[:0]
+	z.closed = true
+	return nil
+}
+
+// encrypted reports whether the stream is encrypted.
+func (z *Writer) encrypted() bool {
+	return len(z.config.passphrase) > 0
+}
+
+// writeFrame codes data into a frame, encrypting it when needed, and writes it. Errors stick,
+// as the stream is unusable after a partial or missing frame.
+func (z *Writer) writeFrame(data []byte) error {
+	err := z.config.ctx.Err()
+	var frame []byte
+	if err == nil {
+		frame, err = encodeFrame(&z.config, data)
+	}
+	if err == nil && z.encrypted() {
+		frame, err = encryption.Encrypt(frame, z.config.passphrase, encryption.DefaultParams)
+	}
+	if err == nil {
+		_, err = z.w.Write(frame)
+	}
+	if err != nil {
+		z.err = err
+		return err
+	}
+	z.written = true
+	z.progress.In += int64(len(data))
+	z.progress.Out += int64(len(frame))
+	z.config.report(z.progress)
+	return nil
+}
@@ -0,0 +1,174 @@
+package huffman
+
+import (
+	"fmt"
+
+	"github.com/Farber98/cc-solutions/compress/bitio"
+)
+
+// Constants for the adaptive alphabet: every byte value plus an end of stream marker.
+const (
+	AdaptiveEOF     = 256
+	adaptiveSymbols = 257
+	adaptiveMaxNode = 2*adaptiveSymbols + 1 // the NYT node stays in the tree after every symbol was seen
+	adaptiveSymBits = 9
+)
+
+// adaptiveNode is a node of the FGK tree. Its position in the node slice is its order number.
+type adaptiveNode struct {
+	weight int
+	parent int
+	left   int
+	right  int
+	symbol int // -1 for internal nodes and for the NYT node
+}
+
+// AdaptiveTree implements FGK adaptive Huffman coding. Encoder and decoder start from the
+// same single NYT (not yet transmitted) node and update the tree after every symbol, so no
+// frequency pass and no code table are needed.
+type AdaptiveTree struct {
+	nodes  []adaptiveNode
+	leaves [adaptiveSymbols]int // node index of each symbol, -1 when not yet seen
+	nyt    int
+}
+
+// NewAdaptiveTree creates a tree containing only the NYT node.
+func NewAdaptiveTree() *AdaptiveTree {
+	t := &AdaptiveTree{nodes: make([]adaptiveNode, adaptiveMaxNode)}
+	root := adaptiveMaxNode - 1
+	t.nodes[root] = adaptiveNode{parent: -1, left: -1, right: -1, symbol: -1}
+	t.nyt = root
+	for i := range t.leaves {
+		t.leaves[i] = -1
+	}
+	return t
+}
+
+// Encode writes the current code of symbol and updates the tree.
+func (t *AdaptiveTree) Encode(symbol int, w *bitio.Writer) error {
+	if symbol < 0 || symbol >= adaptiveSymbols {
+		return fmt.Errorf("symbol out of range: %d", symbol)
+	}
+
+	leaf := t.leaves[symbol]
+	if leaf == -1 {
+		// Unseen symbol: escape through the NYT node followed by the raw symbol
+		if err := t.writeCode(t.nyt, w); err != nil {
+			return err
+		}
+		if err := w.WriteBits(uint64(symbol), adaptiveSymBits); err != nil {
+			return err
+		}
+	} else if err := t.writeCode(leaf, w); err != nil {
+		return err
+	}
+
+	t.update(symbol)
+	return nil
+}
+
+// Decode reads one symbol and updates the tree.
+func (t *AdaptiveTree) Decode(r *bitio.Reader) (int, error) {
+	node := adaptiveMaxNode - 1
+	for t.nodes[node].left != -1 {
+		bit, err := r.ReadBit()
+		if err != nil {
+			return 0, err
+		}
+		if bit == 0 {
+			node = t.nodes[node].left
+		} else {
+			node = t.nodes[node].right
+		}
+	}
+
+	symbol := t.nodes[node].symbol
+	if node == t.nyt {
+		value, err := r.ReadBits(adaptiveSymBits)
+		if err != nil {
+			return 0, err
+		}
+		symbol = int(value)
+		if symbol >= adaptiveSymbols || t.leaves[symbol] != -1 {
+			return 0, fmt.Errorf("invalid escaped symbol: %d", symbol)
+		}
+	}
+
+	t.update(symbol)
+	return symbol, nil
+}
+
+// writeCode writes the path from the root to node, 0 for left and 1 for right.
+func (t *AdaptiveTree) writeCode(node int, w *bitio.Writer) error {
+	var path []uint
+	for node != adaptiveMaxNode-1 {
+		parent := t.nodes[node].parent
+		if t.nodes[parent].right == node {
+			path = append(path, 1)
+		} else {
+			path = append(path, 0)
+		}
+		node = parent
+	}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if err := w.WriteBit(path[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// update increments the weight of symbol, restoring the sibling property on the way up.
+func (t *AdaptiveTree) update(symbol int) {
+	node := t.leaves[symbol]
+	if node == -1 {
+		// The NYT node gives birth to a new NYT node and a leaf for the symbol
+		parent := t.nyt
+		t.nodes[parent].left = parent - 2
+		t.nodes[parent].right = parent - 1
+		t.nodes[parent-1] = adaptiveNode{parent: parent, left: -1, right: -1, symbol: symbol}
+		t.nodes[parent-2] = adaptiveNode{parent: parent, left: -1, right: -1, symbol: -1}
+		t.nyt = parent - 2
+		t.leaves[symbol] = parent - 1
+		node = parent - 1
+	}
+
+	for node != -1 {
+		// Find the highest numbered node with the same weight
+		leader := node
+		for leader+1 < adaptiveMaxNode && t.nodes[leader+1].weight == t.nodes[node].weight {
+			leader++
+		}
+		if leader != node && leader != t.nodes[node].parent {
+			t.swap(node, leader)
+			node = leader
+		}
+
+		t.nodes[node].weight++
+		node = t.nodes[node].parent
+	}
+}
+
+// swap exchanges the subtrees at positions a and b, keeping their parents in place.
+func (t *AdaptiveTree) swap(a, b int) {
+	parentA, parentB := t.nodes[a].parent, t.nodes[b].parent
+	t.nodes[a], t.nodes[b] = t.nodes[b], t.nodes[a]
+	t.nodes[a].parent, t.nodes[b].parent = parentA, parentB
+	t.relink(a)
+	t.relink(b)
+}
+
+// relink points the children or symbol lookup of the node at position i back to i.
+func (t *AdaptiveTree) relink(i int) {
+	n := t.nodes[i]
+	switch {
+	case n.left != -1:
+		t.nodes[n.left].parent = i
+		t.nodes[n.right].parent = i
+	case n.symbol != -1:
+		t.leaves[n.symbol] = i
+	default:
+		t.nyt = i
+	}
+}
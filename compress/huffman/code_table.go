@@ -0,0 +1,147 @@
+package huffman
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// AppendCodeTable appends a binary encoding of codes to buf: a uvarint entry count followed by
+// symbol byte, code length byte and the code bits packed MSB first for every entry. Unlike the
+// text header it can hold any byte, including newlines and commas.
+func AppendCodeTable(buf []byte, codes map[byte]string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(codes)))
+	for char := 0; char < 256; char++ {
+		code, ok := codes[byte(char)]
+		if !ok {
+			continue
+		}
+		buf = append(buf, byte(char), byte(len(code)))
+		buf = AppendPackedCode(buf, code)
+	}
+	return buf
+}
+
+// ReadCodeTable reads a table written by AppendCodeTable and returns it as a reverse lookup table.
+func ReadCodeTable(r io.ByteReader) (map[string]byte, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading code table size: %w", err)
+	}
+	if count > 256 {
+		return nil, fmt.Errorf("invalid code table size: %d", count)
+	}
+
+	codeTable := make(map[string]byte, count)
+	for i := uint64(0); i < count; i++ {
+		char, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("error reading code table: %w", err)
+		}
+		length, err := r.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("error reading code table: %w", err)
+		}
+
+		code, err := ReadPackedCode(r, int(length))
+		if err != nil {
+			return nil, fmt.Errorf("error reading code table: %w", err)
+		}
+		if _, ok := codeTable[code]; ok {
+			return nil, fmt.Errorf("duplicate code in code table: %s", code)
+		}
+		codeTable[code] = char
+	}
+	return codeTable, nil
+}
+
+// AppendSymbolCodeTable appends a binary encoding of codes over an alphabet wider than a byte to
+// buf: a uvarint entry count followed by the uvarint symbol, the uvarint code length and the code
+// bits packed MSB first for every entry, in ascending symbol order.
+func AppendSymbolCodeTable(buf []byte, codes map[int]string) []byte {
+	symbols := make([]int, 0, len(codes))
+	for symbol := range codes {
+		symbols = append(symbols, symbol)
+	}
+	sort.Ints(symbols)
+
+	buf = binary.AppendUvarint(buf, uint64(len(symbols)))
+	for _, symbol := range symbols {
+		code := codes[symbol]
+		buf = binary.AppendUvarint(buf, uint64(symbol))
+		buf = binary.AppendUvarint(buf, uint64(len(code)))
+		buf = AppendPackedCode(buf, code)
+	}
+	return buf
+}
+
+// ReadSymbolCodeTable reads a table written by AppendSymbolCodeTable for an alphabet of
+// alphabetSize symbols and returns it as a reverse lookup table.
+func ReadSymbolCodeTable(r io.ByteReader, alphabetSize int) (map[string]int, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading code table size: %w", err)
+	}
+	if count > uint64(alphabetSize) {
+		return nil, fmt.Errorf("invalid code table size: %d", count)
+	}
+
+	codeTable := make(map[string]int, count)
+	for i := uint64(0); i < count; i++ {
+		symbol, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("error reading code table: %w", err)
+		}
+		if symbol >= uint64(alphabetSize) {
+			return nil, fmt.Errorf("invalid symbol in code table: %d", symbol)
+		}
+		// A tree over n symbols is at most n-1 levels deep
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, fmt.Errorf("error reading code table: %w", err)
+		}
+		if length == 0 || length >= uint64(alphabetSize) {
+			return nil, fmt.Errorf("invalid code length: %d", length)
+		}
+
+		code, err := ReadPackedCode(r, int(length))
+		if err != nil {
+			return nil, fmt.Errorf("error reading code table: %w", err)
+		}
+		if _, ok := codeTable[code]; ok {
+			return nil, fmt.Errorf("duplicate code in code table: %s", code)
+		}
+		codeTable[code] = int(symbol)
+	}
+	return codeTable, nil
+}
+
+// AppendPackedCode appends code, a string of '0' and '1' characters, to buf with its bits packed
+// MSB first into (len(code)+7)/8 bytes.
+func AppendPackedCode(buf []byte, code string) []byte {
+	packed := make([]byte, (len(code)+7)/8)
+	for i, bit := range code {
+		if bit == '1' {
+			packed[i/8] |= 1 << (7 - i%8)
+		}
+	}
+	return append(buf, packed...)
+}
+
+// ReadPackedCode reads a code of length bits written by AppendPackedCode and returns it as a
+// string of '0' and '1' characters.
+func ReadPackedCode(r io.ByteReader, length int) (string, error) {
+	code := make([]byte, length)
+	var packed byte
+	for i := range code {
+		if i%8 == 0 {
+			var err error
+			if packed, err = r.ReadByte(); err != nil {
+				return "", err
+			}
+		}
+		code[i] = '0' + (packed>>(7-i%8))&1
+	}
+	return string(code), nil
+}
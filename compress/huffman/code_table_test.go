@@ -0,0 +1,75 @@
+package huffman
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+)
+
+func TestSymbolCodeTableRoundTrip(t *testing.T) {
+	frequencies := map[int]int{'a': 10, 'b': 3, 300: 7, 301: 1, 1000: 2}
+	codes := make(map[int]string)
+	h := &DefaultHuffmanCoding{}
+	h.AssignSymbolCodes(h.BuildSymbolTree(frequencies), "", codes)
+
+	table, err := ReadSymbolCodeTable(bytes.NewReader(AppendSymbolCodeTable(nil, codes)), 1001)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(table) != len(codes) {
+		t.Fatalf("Expected %d entries, got %d", len(codes), len(table))
+	}
+	for symbol, code := range codes {
+		if table[code] != symbol {
+			t.Errorf("Expected code %s to map to %d, got %d", code, symbol, table[code])
+		}
+	}
+}
+
+func TestReadSymbolCodeTableErrors(t *testing.T) {
+	entry := func(symbol, length uint64, packed ...byte) []byte {
+		buf := binary.AppendUvarint(nil, symbol)
+		buf = binary.AppendUvarint(buf, length)
+		return append(buf, packed...)
+	}
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		expectedError string
+	}{
+		{name: "Too_many_entries", data: []byte{11}, expectedError: "invalid code table size: 11"},
+		{name: "Symbol_outside_alphabet", data: append([]byte{1}, entry(10, 1, 0)...), expectedError: "invalid symbol in code table: 10"},
+		{name: "Empty_code", data: append([]byte{1}, entry(3, 0)...), expectedError: "invalid code length: 0"},
+		{name: "Code_too_long", data: append([]byte{1}, entry(3, 10, 0, 0)...), expectedError: "invalid code length: 10"},
+		{name: "Duplicate_code", data: append(append([]byte{2}, entry(3, 1, 0x80)...), entry(4, 1, 0x80)...), expectedError: "duplicate code"},
+		{name: "Truncated", data: append([]byte{1}, entry(3, 9, 0)...), expectedError: "error reading code table"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ReadSymbolCodeTable(bytes.NewReader(tc.data), 10)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestPackedCodeRoundTrip(t *testing.T) {
+	for _, code := range []string{"", "0", "1", "10110", "10000001", "111111110", "0101010101010101011"} {
+		packed := AppendPackedCode([]byte{0xAA}, code)
+		if len(packed) != 1+(len(code)+7)/8 {
+			t.Errorf("Expected %q to pack into %d bytes, got %d", code, (len(code)+7)/8, len(packed)-1)
+		}
+
+		read, err := ReadPackedCode(bytes.NewReader(packed[1:]), len(code))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if read != code {
+			t.Errorf("Expected %q, got %q", code, read)
+		}
+	}
+}
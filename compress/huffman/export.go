@@ -0,0 +1,97 @@
+package huffman
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Farber98/cc-solutions/compress/priority_queue"
+)
+
+// TreeNode is the JSON representation of a Huffman tree node. Leaves have a symbol and no
+// children, internal nodes have both children.
+type TreeNode struct {
+	Freq int `json:"freq"`
+	// Code is the path from the root, 0 for left and 1 for right.
+	Code   string `json:"code"`
+	Symbol *byte  `json:"symbol,omitempty"`
+	// Display is the symbol as a quoted Go string, so non-printable bytes stay readable.
+	Display string    `json:"display,omitempty"`
+	Left    *TreeNode `json:"left,omitempty"`
+	Right   *TreeNode `json:"right,omitempty"`
+}
+
+// NewTreeNode converts the tree below node into its JSON representation. A nil tree becomes nil.
+func NewTreeNode(node *priority_queue.Node, code string) *TreeNode {
+	if node == nil {
+		return nil
+	}
+
+	n := &TreeNode{Freq: node.Freq, Code: code}
+	if node.Left == nil && node.Right == nil {
+		n.Code = LeafCode(code)
+		char := byte(node.Symbol)
+		n.Symbol = &char
+		n.Display = strconv.Quote(string([]byte{char}))
+		return n
+	}
+	if node.Left != nil {
+		n.Left = NewTreeNode(node.Left, code+"0")
+	}
+	if node.Right != nil {
+		n.Right = NewTreeNode(node.Right, code+"1")
+	}
+	return n
+}
+
+// WriteJSON writes the tree below root as indented JSON, null for an empty tree.
+func WriteJSON(w io.Writer, root *priority_queue.Node) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(NewTreeNode(root, ""))
+}
+
+// WriteDOT writes the tree below root as a Graphviz digraph. Internal nodes are labeled with
+// their frequency, leaves with their symbol, frequency and code, and edges with 0 or 1.
+// An empty tree produces an empty graph.
+func WriteDOT(w io.Writer, root *priority_queue.Node) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph huffman {")
+	fmt.Fprintln(bw, "\tnode [fontname=\"monospace\"];")
+
+	id := 0
+	var walk func(node *priority_queue.Node, code string) int
+	walk = func(node *priority_queue.Node, code string) int {
+		self := id
+		id++
+		if node.Left == nil && node.Right == nil {
+			symbol := escapeDOT(strconv.Quote(string([]byte{byte(node.Symbol)})))
+			fmt.Fprintf(bw, "\tn%d [shape=box, label=\"%s\\n%d\\n%s\"];\n", self, symbol, node.Freq, LeafCode(code))
+			return self
+		}
+
+		fmt.Fprintf(bw, "\tn%d [shape=circle, label=\"%d\"];\n", self, node.Freq)
+		for bit, child := range []*priority_queue.Node{node.Left, node.Right} {
+			if child == nil {
+				continue
+			}
+			childID := walk(child, code+strconv.Itoa(bit))
+			fmt.Fprintf(bw, "\tn%d -> n%d [label=\"%d\"];\n", self, childID, bit)
+		}
+		return self
+	}
+	if root != nil {
+		walk(root, "")
+	}
+
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// escapeDOT escapes the quotes and backslashes of s for use inside a quoted DOT label.
+func escapeDOT(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
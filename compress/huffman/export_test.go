@@ -0,0 +1,83 @@
+package huffman
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Farber98/cc-solutions/compress/priority_queue"
+)
+
+// exampleTree returns a small fixed tree: 'a' gets code 0, '\n' gets 10 and '"' gets 11.
+func exampleTree() *priority_queue.Node {
+	return &priority_queue.Node{
+		Freq: 6,
+		Left: &priority_queue.Node{Symbol: 'a', Freq: 3},
+		Right: &priority_queue.Node{
+			Freq:  3,
+			Left:  &priority_queue.Node{Symbol: '\n', Freq: 1},
+			Right: &priority_queue.Node{Symbol: '"', Freq: 2},
+		},
+	}
+}
+
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, exampleTree()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"digraph huffman {",
+		`n0 [shape=circle, label="6"];`,
+		`n1 [shape=box, label="\"a\"\n3\n0"];`,
+		`n0 -> n1 [label="0"];`,
+		`n3 [shape=box, label="\"\\n\"\n1\n10"];`,
+		`n4 [shape=box, label="\"\\\"\"\n2\n11"];`,
+		`n0 -> n2 [label="1"];`,
+	}
+	for _, line := range expected {
+		if !strings.Contains(buf.String(), line) {
+			t.Errorf("Expected DOT output to contain %s, got:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, exampleTree()); err != nil {
+		t.Fatal(err)
+	}
+
+	var root TreeNode
+	if err := json.Unmarshal(buf.Bytes(), &root); err != nil {
+		t.Fatalf("Expected valid JSON, got %v", err)
+	}
+	if root.Freq != 6 || root.Symbol != nil {
+		t.Errorf("Expected internal root with frequency 6, got %+v", root)
+	}
+	leaf := root.Right.Left
+	if leaf.Symbol == nil || *leaf.Symbol != '\n' || leaf.Code != "10" || leaf.Display != `"\n"` {
+		t.Errorf("Expected newline leaf with code 10, got %+v", leaf)
+	}
+}
+
+func TestExportEdgeCases(t *testing.T) {
+	var dot, js bytes.Buffer
+	if err := WriteDOT(&dot, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dot.String(), "digraph huffman {") || strings.Contains(dot.String(), "n0") {
+		t.Errorf("Expected an empty graph, got %q", dot.String())
+	}
+	if err := WriteJSON(&js, nil); err != nil || strings.TrimSpace(js.String()) != "null" {
+		t.Errorf("Expected null, got %q (%v)", js.String(), err)
+	}
+
+	// A lone leaf shows the code the coder actually uses
+	leaf := NewTreeNode(&priority_queue.Node{Symbol: 'x', Freq: 5}, "")
+	if leaf.Code != "0" {
+		t.Errorf("Expected code 0 for a root leaf, got %q", leaf.Code)
+	}
+}
@@ -10,15 +10,37 @@ import (
 type HuffmanCoding interface {
 	BuildHuffmanTree(frequencies map[byte]int) *priority_queue.Node
 	AssignCodes(node *priority_queue.Node, code string, codes map[byte]string)
+	BuildSymbolTree(frequencies map[int]int) *priority_queue.Node
+	AssignSymbolCodes(node *priority_queue.Node, code string, codes map[int]string)
 }
 
 // DefaultCalculator implements the Calculator interface with default frequency calculation.
 type DefaultHuffmanCoding struct{}
 
 // BuildHuffmanTree builds a Huffman tree from the given character frequencies.
+// It returns nil when there are no frequencies, i.e. for empty input.
 func (h *DefaultHuffmanCoding) BuildHuffmanTree(frequencies map[byte]int) *priority_queue.Node {
+	if len(frequencies) == 0 {
+		return nil
+	}
+
 	// Populate priority queue with nodes for each character frequency
-	pq := priority_queue.NewPriorityQueue(frequencies)
+	return buildTree(priority_queue.NewPriorityQueue(frequencies))
+}
+
+// BuildSymbolTree builds a Huffman tree from the given symbol frequencies, for alphabets that do
+// not fit in a byte. It returns nil when there are no frequencies.
+func (h *DefaultHuffmanCoding) BuildSymbolTree(frequencies map[int]int) *priority_queue.Node {
+	if len(frequencies) == 0 {
+		return nil
+	}
+	return buildTree(priority_queue.NewSymbolPriorityQueue(frequencies))
+}
+
+// buildTree merges the nodes of pq into a single Huffman tree and returns its root.
+func buildTree(pq priority_queue.PriorityQueue) *priority_queue.Node {
+	// Merged nodes are ordered after every leaf and after each other, which makes the tree deterministic
+	order := pq.Len()
 
 	// Build Huffman tree by merging nodes until we only have one node.
 	for pq.Len() > 1 {
@@ -28,26 +50,33 @@ func (h *DefaultHuffmanCoding) BuildHuffmanTree(frequencies map[byte]int) *prior
 
 		// Create a new node with the sum of the frequencies
 		merged := &priority_queue.Node{
-			Char:     0, // Internal node, not a character
+			Symbol:   0, // Internal node, not a symbol
 			Freq:     minimum.Freq + nextMinimum.Freq,
 			Priority: minimum.Freq + nextMinimum.Freq,
+			Order:    order,
 			Left:     minimum,
 			Right:    nextMinimum,
 		}
 
 		// Push the merged node back into the priority queue
 		heap.Push(&pq, merged)
+		order++
 	}
 
 	// Return the root of the Huffman tree
 	return heap.Pop(&pq).(*priority_queue.Node)
 }
 
-// AssignCodes traverses the Huffman tree to assign binary codes to each character.
+// AssignCodes traverses the Huffman tree to assign binary codes to each character. A nil tree
+// assigns nothing, and a tree that is a single leaf gives its character the one bit code "0".
 func (h *DefaultHuffmanCoding) AssignCodes(node *priority_queue.Node, code string, codes map[byte]string) {
+	if node == nil {
+		return
+	}
+
 	// Base case: if the node is a leaf (character node), assign the code to the character
 	if node.Left == nil && node.Right == nil {
-		codes[node.Char] = code
+		codes[byte(node.Symbol)] = LeafCode(code)
 		return
 	}
 
@@ -61,3 +90,30 @@ func (h *DefaultHuffmanCoding) AssignCodes(node *priority_queue.Node, code strin
 		h.AssignCodes(node.Right, code+"1", codes)
 	}
 }
+
+// AssignSymbolCodes traverses the Huffman tree to assign binary codes to each symbol, like AssignCodes.
+func (h *DefaultHuffmanCoding) AssignSymbolCodes(node *priority_queue.Node, code string, codes map[int]string) {
+	if node == nil {
+		return
+	}
+
+	if node.Left == nil && node.Right == nil {
+		codes[node.Symbol] = LeafCode(code)
+		return
+	}
+	if node.Left != nil {
+		h.AssignSymbolCodes(node.Left, code+"0", codes)
+	}
+	if node.Right != nil {
+		h.AssignSymbolCodes(node.Right, code+"1", codes)
+	}
+}
+
+// LeafCode returns the code of a leaf reached by path. Only a root leaf has an empty path, and
+// an empty code would never consume a bit, so it is coded as "0" instead.
+func LeafCode(path string) string {
+	if path == "" {
+		return "0"
+	}
+	return path
+}
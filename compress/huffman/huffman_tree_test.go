@@ -1,7 +1,10 @@
 package huffman
 
 import (
+	"math"
+	"reflect"
 	"testing"
+	"testing/quick"
 )
 
 func TestBuildHuffmanTree(t *testing.T) {
@@ -74,3 +77,92 @@ func TestAssignCodes(t *testing.T) {
 		}
 	}
 }
+
+func TestHuffmanEdgeCases(t *testing.T) {
+	uniform := make(map[byte]int)
+	for char := 0; char < 256; char++ {
+		uniform[byte(char)] = 7
+	}
+
+	testCases := []struct {
+		name        string
+		frequencies map[byte]int
+		expected    map[byte]string
+	}{
+		{name: "Empty", frequencies: map[byte]int{}, expected: map[byte]string{}},
+		{name: "SingleSymbol", frequencies: map[byte]int{'x': 42}, expected: map[byte]string{'x': "0"}},
+		{name: "TwoSymbols", frequencies: map[byte]int{'x': 42, 'y': 1}, expected: map[byte]string{'y': "0", 'x': "1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &DefaultHuffmanCoding{}
+			codes := make(map[byte]string)
+			h.AssignCodes(h.BuildHuffmanTree(tc.frequencies), "", codes)
+			if !reflect.DeepEqual(codes, tc.expected) {
+				t.Errorf("Expected codes %v, got %v", tc.expected, codes)
+			}
+		})
+	}
+
+	t.Run("AllSymbols", func(t *testing.T) {
+		h := &DefaultHuffmanCoding{}
+		codes := make(map[byte]string)
+		h.AssignCodes(h.BuildHuffmanTree(uniform), "", codes)
+		if len(codes) != 256 {
+			t.Fatalf("Expected 256 codes, got %d", len(codes))
+		}
+		for char, code := range codes {
+			if len(code) != 8 {
+				t.Errorf("Expected an 8 bit code for %d, got %q", char, code)
+			}
+		}
+	})
+}
+
+func TestAssignCodesComplete(t *testing.T) {
+	// Property: for any frequencies with at least two symbols the codes form a complete
+	// prefix code, i.e. the Kraft sum of 2^-length over all codes is exactly one.
+	property := func(counts []uint16) bool {
+		frequencies := make(map[byte]int)
+		for i, count := range counts {
+			frequencies[byte(i)] = int(count) + 1
+		}
+		if len(frequencies) < 2 {
+			return true
+		}
+
+		h := &DefaultHuffmanCoding{}
+		codes := make(map[byte]string)
+		h.AssignCodes(h.BuildHuffmanTree(frequencies), "", codes)
+
+		kraft := 0.0
+		for _, code := range codes {
+			kraft += math.Pow(2, -float64(len(code)))
+		}
+		return len(codes) == len(frequencies) && math.Abs(kraft-1) < 1e-9
+	}
+
+	if err := quick.Check(property, &quick.Config{MaxCount: 300}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestAssignSymbolCodes(t *testing.T) {
+	// Symbols beyond the byte range, e.g. the indices of a word vocabulary
+	frequencies := map[int]int{
+		0:    12,
+		300:  8,
+		1000: 1,
+		70:   3,
+	}
+
+	h := &DefaultHuffmanCoding{}
+	codes := make(map[int]string)
+	h.AssignSymbolCodes(h.BuildSymbolTree(frequencies), "", codes)
+
+	expected := map[int]string{0: "0", 300: "11", 70: "101", 1000: "100"}
+	if !reflect.DeepEqual(codes, expected) {
+		t.Errorf("Expected codes %v, got %v", expected, codes)
+	}
+}
@@ -0,0 +1,64 @@
+// Package atomicfile writes files through a temporary file that is renamed into place, shared by
+// the compress commands and package archive.
+package atomicfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ChunkSize is the amount of data Write writes between checks for cancellation.
+const ChunkSize = 1 << 20
+
+// Write writes data to path with mode perm through a temporary file in the same directory, so
+// that an existing file is never left half written. Existing files are only replaced when force
+// is set, otherwise the error wraps fs.ErrExist. Once ctx is done the temporary file is removed
+// and path is left as it was.
+func Write(ctx context.Context, path string, data []byte, perm fs.FileMode, force bool) error {
+	if !force {
+		if _, err := os.Lstat(path); err == nil {
+			return fmt.Errorf("%s: %w", path, fs.ErrExist)
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("error creating output: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	for start := 0; ; start += ChunkSize {
+		if err := ctx.Err(); err != nil {
+			tmp.Close()
+			return err
+		}
+		if start >= len(data) {
+			break
+		}
+		end := start + ChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if _, err := tmp.Write(data[start:end]); err != nil {
+			tmp.Close()
+			return fmt.Errorf("error writing output: %w", err)
+		}
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing output: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("error writing output: %w", err)
+	}
+	return nil
+}
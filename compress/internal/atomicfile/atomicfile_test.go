@@ -0,0 +1,54 @@
+package atomicfile
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output")
+	if err := Write(context.Background(), path, []byte("first"), 0600, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("Expected a file with mode 0600, got %v %v", info, err)
+	}
+
+	// Existing files are only replaced when forced
+	if err := Write(context.Background(), path, []byte("second"), 0600, false); !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Expected %v, got %v", fs.ErrExist, err)
+	}
+	if contents, _ := os.ReadFile(path); string(contents) != "first" {
+		t.Errorf("Expected the existing file to be kept, got %q", contents)
+	}
+	if err := Write(context.Background(), path, []byte("second"), 0600, true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if contents, _ := os.ReadFile(path); string(contents) != "second" {
+		t.Errorf("Expected the file to be replaced, got %q", contents)
+	}
+}
+
+func TestWrite_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	path := filepath.Join(t.TempDir(), "existing")
+	os.WriteFile(path, []byte("keep me"), 0644)
+	if err := Write(ctx, path, bytes.Repeat([]byte("x"), 3*ChunkSize), 0644, true); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+
+	// The existing file is untouched and the temporary file is gone
+	if contents, _ := os.ReadFile(path); string(contents) != "keep me" {
+		t.Errorf("Expected the existing file to be kept, got %q", contents)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("Expected no temporary file, got %v", entries)
+	}
+}
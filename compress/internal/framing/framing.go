@@ -0,0 +1,93 @@
+// Package framing codes single frames of the container format, shared by the compress command
+// and package huff.
+package framing
+
+import (
+	"bytes"
+	"fmt"
+
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/pipeline"
+)
+
+// Encode runs the stages and the compressor over data and returns a complete sized frame.
+func Encode(method container.Method, compressor compress.Compressor, p pipeline.Pipeline, data []byte) ([]byte, error) {
+	transformed, err := p.Forward(data)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := compressor.Encode(transformed, nil)
+	var buffer bytes.Buffer
+	header := container.Header{
+		Method:      method,
+		Stages:      p.IDs(),
+		Length:      uint64(len(data)),
+		Checksum:    container.Checksum(data),
+		Sized:       true,
+		PayloadSize: uint64(len(payload)),
+	}
+	if err := container.WriteHeader(&buffer, header); err != nil {
+		return nil, fmt.Errorf("error writing header: %w", err)
+	}
+	buffer.Write(payload)
+	return buffer.Bytes(), nil
+}
+
+// Decode decodes an unencrypted frame with the decompressor newDecompressor returns for its
+// method and the stages recorded in its header, and verifies the result against the stored
+// length and checksum. The decompressor is limited to the length the stages can make of the
+// frame, so a crafted payload fails before it takes more memory than its header claims.
+func Decode(frame []byte, newDecompressor func(container.Method) (compress.LimitedDecompressor, error)) ([]byte, error) {
+	reader := bytes.NewReader(frame)
+	header, err := container.ReadHeader(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+	p, err := pipeline.FromIDs(header.Stages)
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+	decompressor, err := newDecompressor(header.Method)
+	if err != nil {
+		return nil, fmt.Errorf("error reading header: %w", err)
+	}
+	// The payload decodes to at most what the stages make of the frame
+	decompressor.SetMaxLength(p.MaxForwardLength(header.Length))
+
+	decoded, err := decompressor.Decode(frame[len(frame)-reader.Len():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding text: %w", err)
+	}
+	if decoded, err = p.Inverse(decoded); err != nil {
+		return nil, fmt.Errorf("error decoding text: %w", err)
+	}
+	if err := header.Verify(decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// NewDecompressor returns the decompressor for a method whose frames decode on their own.
+// Dictionary and delta frames need the dictionary or the reference, so callers handle them.
+func NewDecompressor(method container.Method) (compress.LimitedDecompressor, error) {
+	switch method {
+	case container.MethodHuffman:
+		return &compress.HuffmanDecompressor{}, nil
+	case container.MethodAdaptive:
+		return &compress.AdaptiveDecompressor{}, nil
+	case container.MethodRange:
+		return &compress.RangeDecompressor{}, nil
+	case container.MethodWord:
+		return &compress.WordDecompressor{}, nil
+	case container.MethodSeekable:
+		return &compress.SeekableDecompressor{}, nil
+	case container.MethodBlocks:
+		return &compress.BlocksDecompressor{}, nil
+	case container.MethodRLE:
+		return &compress.RLEDecompressor{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported method: %v", method)
+	}
+}
@@ -0,0 +1,100 @@
+package framing
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/pipeline"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	data := bytes.Repeat([]byte("frames share one format. "), 100)
+	p, err := pipeline.Parse("bwt,mtf")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	frame, err := Encode(container.MethodRange, &compress.RangeCompressor{}, p, data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	decoded, err := Decode(frame, NewDecompressor)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Error("Expected the decoded frame to match the data")
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	data := []byte("checked against the header")
+	frame, err := Encode(container.MethodHuffman, &compress.HuffmanCompressor{}, nil, data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	corrupt := bytes.Clone(frame)
+	corrupt[len(corrupt)-1] ^= 0xff
+	dictionary, err := Encode(container.MethodDictionary, &compress.HuffmanCompressor{}, nil, data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	testCases := []struct {
+		name          string
+		frame         []byte
+		expectedError string
+	}{
+		{name: "Truncated", frame: frame[:3], expectedError: "error reading header"},
+		{name: "Corrupt", frame: corrupt, expectedError: "checksum mismatch"},
+		{name: "Dictionary", frame: dictionary, expectedError: "unsupported method"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Decode(tc.frame, NewDecompressor)
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected an error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestDecode_RunPastLength(t *testing.T) {
+	// A frame claiming less than its runs expand to fails before the runs are expanded
+	payload := (&compress.RLECompressor{}).Encode(make([]byte, 1<<20), nil)
+	var frame bytes.Buffer
+	header := container.Header{Method: container.MethodRLE, Length: 1000, Sized: true, PayloadSize: uint64(len(payload))}
+	if err := container.WriteHeader(&frame, header); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	frame.Write(payload)
+
+	_, err := Decode(frame.Bytes(), NewDecompressor)
+	if err == nil || !strings.Contains(err.Error(), "more than the 1000 allowed") {
+		t.Errorf("Expected an error for a run past the frame length, got %v", err)
+	}
+}
+
+func TestDecode_RunsAfterStages(t *testing.T) {
+	// BWT makes the data longer than the frame, the runs are bounded by what it can make of it
+	data := []byte("aaaaaaaaaaaaaaaaaaaaaaaaaaaa")
+	p, err := pipeline.Parse("bwt")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	frame, err := Encode(container.MethodRLE, &compress.RLECompressor{}, p, data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	decoded, err := Decode(frame, NewDecompressor)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.Equal(decoded, data) {
+		t.Error("Expected the decoded frame to match the data")
+	}
+}
@@ -0,0 +1,235 @@
+package pipeline
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// DefaultBlockSize is the BWT block size, the same as bzip2 -9.
+const DefaultBlockSize = 900 * 1000
+
+// BWT implements the Burrows-Wheeler transform. It groups bytes that occur in similar contexts,
+// which turns the repetitions of text into runs that MoveToFront and ZeroRunLength can exploit.
+type BWT struct {
+	// BlockSize is the number of bytes transformed at once. Larger blocks compress better but use more memory.
+	BlockSize int
+}
+
+// ID implements Stage.
+func (b *BWT) ID() byte { return 'B' }
+
+// Name implements Stage.
+func (b *BWT) Name() string { return "bwt" }
+
+// MaxForwardLength implements Stage. Every block adds its length and primary index.
+func (b *BWT) MaxForwardLength(n uint64) uint64 {
+	blockSize := uint64(b.BlockSize)
+	if b.BlockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+	blocks := n/blockSize + 1
+	if n > math.MaxUint64-blocks*2*binary.MaxVarintLen64 {
+		return math.MaxUint64
+	}
+	return n + blocks*2*binary.MaxVarintLen64
+}
+
+// Forward transforms the data block by block. Every block is written as uvarint length,
+// uvarint primary index and the last column of the sorted rotations.
+func (b *BWT) Forward(data []byte) ([]byte, error) {
+	blockSize := b.BlockSize
+	if blockSize <= 0 {
+		blockSize = DefaultBlockSize
+	}
+
+	out := make([]byte, 0, len(data)+16)
+	for start := 0; start < len(data); start += blockSize {
+		end := start + blockSize
+		if end > len(data) {
+			end = len(data)
+		}
+		lastColumn, primary := bwtBlock(data[start:end])
+		out = binary.AppendUvarint(out, uint64(len(lastColumn)))
+		out = binary.AppendUvarint(out, uint64(primary))
+		out = append(out, lastColumn...)
+	}
+	return out, nil
+}
+
+// Inverse rebuilds the original data from the transformed blocks.
+func (b *BWT) Inverse(data []byte) ([]byte, error) {
+	reader := bytes.NewReader(data)
+	var out []byte
+	for reader.Len() > 0 {
+		length, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("error reading block length: %w", err)
+		}
+		primary, err := binary.ReadUvarint(reader)
+		if err != nil {
+			return nil, fmt.Errorf("error reading primary index: %w", err)
+		}
+		if length > uint64(reader.Len()) || primary > length || primary == 0 && length > 0 {
+			return nil, fmt.Errorf("invalid block header")
+		}
+
+		offset := len(data) - reader.Len()
+		out = append(out, inverseBwtBlock(data[offset:offset+int(length)], int(primary))...)
+		reader.Seek(int64(length), 1)
+	}
+	return out, nil
+}
+
+// bwtBlock returns the last column of the sorted rotations of block followed by a virtual
+// end marker that sorts before every byte. The marker itself is left out and its row is
+// returned as the primary index.
+func bwtBlock(block []byte) ([]byte, int) {
+	n := len(block)
+	sa := suffixArray(block)
+
+	// Row 0 is the rotation starting with the end marker, its last byte is the last byte of the block
+	lastColumn := make([]byte, 0, n)
+	primary := 0
+	if n > 0 {
+		lastColumn = append(lastColumn, block[n-1])
+	}
+	for i, pos := range sa {
+		if pos == 0 {
+			primary = i + 1
+			continue
+		}
+		lastColumn = append(lastColumn, block[pos-1])
+	}
+	return lastColumn, primary
+}
+
+// inverseBwtBlock walks the last to first mapping backwards from the end marker row.
+func inverseBwtBlock(lastColumn []byte, primary int) []byte {
+	n := len(lastColumn)
+	if n == 0 {
+		return nil
+	}
+
+	// Symbols are shifted by one so that the end marker can be 0
+	symbol := func(row int) int {
+		switch {
+		case row == primary:
+			return 0
+		case row < primary:
+			return int(lastColumn[row]) + 1
+		default:
+			return int(lastColumn[row-1]) + 1
+		}
+	}
+
+	var counts [257]int
+	occurrences := make([]int, n+1)
+	for row := 0; row <= n; row++ {
+		s := symbol(row)
+		occurrences[row] = counts[s]
+		counts[s]++
+	}
+
+	var firsts [257]int
+	for s := 1; s < 257; s++ {
+		firsts[s] = firsts[s-1] + counts[s-1]
+	}
+
+	out := make([]byte, n)
+	row := 0
+	for i := n - 1; i >= 0; i-- {
+		s := symbol(row)
+		out[i] = byte(s - 1)
+		row = firsts[s] + occurrences[row]
+	}
+	return out
+}
+
+// suffixArray sorts the suffixes of data by prefix doubling with counting sorts, in O(n log n).
+// A suffix that is a prefix of another one sorts first.
+func suffixArray(data []byte) []int {
+	n := len(data)
+	sa := make([]int, n)
+	classes := make([]int, n)
+	if n == 0 {
+		return sa
+	}
+
+	// Sort by the first byte
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	for i := 1; i < 256; i++ {
+		counts[i] += counts[i-1]
+	}
+	for i := n - 1; i >= 0; i-- {
+		counts[data[i]]--
+		sa[counts[data[i]]] = i
+	}
+	numClasses := 1
+	classes[sa[0]] = 0
+	for i := 1; i < n; i++ {
+		if data[sa[i]] != data[sa[i-1]] {
+			numClasses++
+		}
+		classes[sa[i]] = numClasses - 1
+	}
+
+	byRank := make([]int, n)
+	newClasses := make([]int, n)
+	counter := make([]int, n)
+	second := func(i, k int) int {
+		if i+k < n {
+			return classes[i+k]
+		}
+		return -1
+	}
+
+	for k := 1; numClasses < n; k <<= 1 {
+		// Order by the second half: suffixes shorter than k first, then by the current order
+		idx := 0
+		for i := n - k; i < n; i++ {
+			if i >= 0 {
+				byRank[idx] = i
+				idx++
+			}
+		}
+		for _, pos := range sa {
+			if pos >= k {
+				byRank[idx] = pos - k
+				idx++
+			}
+		}
+
+		// Stable counting sort by the first half
+		for i := range counter[:numClasses] {
+			counter[i] = 0
+		}
+		for _, pos := range byRank {
+			counter[classes[pos]]++
+		}
+		for i := 1; i < numClasses; i++ {
+			counter[i] += counter[i-1]
+		}
+		for i := n - 1; i >= 0; i-- {
+			pos := byRank[i]
+			counter[classes[pos]]--
+			sa[counter[classes[pos]]] = pos
+		}
+
+		numClasses = 1
+		newClasses[sa[0]] = 0
+		for i := 1; i < n; i++ {
+			cur, prev := sa[i], sa[i-1]
+			if classes[cur] != classes[prev] || second(cur, k) != second(prev, k) {
+				numClasses++
+			}
+			newClasses[cur] = numClasses - 1
+		}
+		classes, newClasses = newClasses, classes
+	}
+	return sa
+}
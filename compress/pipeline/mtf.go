@@ -0,0 +1,52 @@
+package pipeline
+
+// MoveToFront implements the move-to-front transform. Every byte is replaced by its position in
+// a list of recently used bytes, so the runs produced by BWT become runs of zeros.
+type MoveToFront struct{}
+
+// ID implements Stage.
+func (m *MoveToFront) ID() byte { return 'M' }
+
+// Name implements Stage.
+func (m *MoveToFront) Name() string { return "mtf" }
+
+// MaxForwardLength implements Stage. The output is as long as the input.
+func (m *MoveToFront) MaxForwardLength(n uint64) uint64 { return n }
+
+// Forward replaces every byte by its current index in the recency list.
+func (m *MoveToFront) Forward(data []byte) ([]byte, error) {
+	order := initialOrder()
+	out := make([]byte, len(data))
+	for i, b := range data {
+		j := 0
+		for order[j] != b {
+			j++
+		}
+		out[i] = byte(j)
+		copy(order[1:j+1], order[:j])
+		order[0] = b
+	}
+	return out, nil
+}
+
+// Inverse replaces every index by the byte it points to in the recency list.
+func (m *MoveToFront) Inverse(data []byte) ([]byte, error) {
+	order := initialOrder()
+	out := make([]byte, len(data))
+	for i, j := range data {
+		b := order[j]
+		out[i] = b
+		copy(order[1:int(j)+1], order[:j])
+		order[0] = b
+	}
+	return out, nil
+}
+
+// initialOrder returns the recency list both sides start from.
+func initialOrder() [256]byte {
+	var order [256]byte
+	for i := range order {
+		order[i] = byte(i)
+	}
+	return order
+}
@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stage defines a reversible transform applied to the data before entropy coding.
+type Stage interface {
+	// ID identifies the stage in the frame header.
+	ID() byte
+	// Name identifies the stage on the command line.
+	Name() string
+	// Forward transforms the original data.
+	Forward(data []byte) ([]byte, error)
+	// Inverse undoes Forward.
+	Inverse(data []byte) ([]byte, error)
+	// MaxForwardLength returns the longest output of Forward for n bytes, so decoders can
+	// bound what a frame of n bytes decodes to before the stages are undone.
+	MaxForwardLength(n uint64) uint64
+}
+
+// Pipeline is an ordered list of stages. Forward runs them in order, Inverse in reverse order.
+type Pipeline []Stage
+
+var stages = make(map[byte]Stage)
+
+// Register registers a stage, making it available to Parse and FromIDs.
+func Register(stage Stage) {
+	stages[stage.ID()] = stage
+}
+
+func init() {
+	Register(&BWT{BlockSize: DefaultBlockSize})
+	Register(&MoveToFront{})
+	Register(&ZeroRunLength{})
+}
+
+// Parse builds a pipeline from a comma separated list of stage names, e.g. "bwt,mtf,zrle".
+func Parse(names string) (Pipeline, error) {
+	var p Pipeline
+	if names == "" {
+		return p, nil
+	}
+
+	for _, name := range strings.Split(names, ",") {
+		stage, err := byName(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		p = append(p, stage)
+	}
+	return p, nil
+}
+
+// byName looks up a registered stage by its name.
+func byName(name string) (Stage, error) {
+	for _, stage := range stages {
+		if stage.Name() == name {
+			return stage, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown stage: %s", name)
+}
+
+// FromIDs builds a pipeline from the stage IDs stored in a frame header.
+func FromIDs(ids []byte) (Pipeline, error) {
+	var p Pipeline
+	for _, id := range ids {
+		stage, ok := stages[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown stage id: %d", id)
+		}
+		p = append(p, stage)
+	}
+	return p, nil
+}
+
+// MaxForwardLength returns the longest output of Forward for n bytes.
+func (p Pipeline) MaxForwardLength(n uint64) uint64 {
+	for _, stage := range p {
+		n = stage.MaxForwardLength(n)
+	}
+	return n
+}
+
+// IDs returns the stage IDs to store in a frame header.
+func (p Pipeline) IDs() []byte {
+	ids := make([]byte, len(p))
+	for i, stage := range p {
+		ids[i] = stage.ID()
+	}
+	return ids
+}
+
+// String returns the comma separated stage names.
+func (p Pipeline) String() string {
+	names := make([]string, len(p))
+	for i, stage := range p {
+		names[i] = stage.Name()
+	}
+	return strings.Join(names, ",")
+}
+
+// Forward runs every stage in order.
+func (p Pipeline) Forward(data []byte) ([]byte, error) {
+	var err error
+	for _, stage := range p {
+		data, err = stage.Forward(data)
+		if err != nil {
+			return nil, fmt.Errorf("error running stage %s: %w", stage.Name(), err)
+		}
+	}
+	return data, nil
+}
+
+// Inverse undoes every stage in reverse order.
+func (p Pipeline) Inverse(data []byte) ([]byte, error) {
+	var err error
+	for i := len(p) - 1; i >= 0; i-- {
+		data, err = p[i].Inverse(data)
+		if err != nil {
+			return nil, fmt.Errorf("error inverting stage %s: %w", p[i].Name(), err)
+		}
+	}
+	return data, nil
+}
@@ -0,0 +1,141 @@
+package pipeline
+
+import (
+	"bytes"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func testInputs() map[string][]byte {
+	random := make([]byte, 5000)
+	rand.New(rand.NewSource(1)).Read(random)
+
+	return map[string][]byte{
+		"Empty":        {},
+		"SingleByte":   []byte("a"),
+		"SingleSymbol": bytes.Repeat([]byte{0}, 1000),
+		"Banana":       []byte("banana"),
+		"Text":         []byte(strings.Repeat("the quick brown fox jumps over the lazy dog\n", 50)),
+		"Random":       random,
+		"LoneZeros":    bytes.Repeat([]byte{0, 1}, 500),
+	}
+}
+
+func TestStagesRoundTrip(t *testing.T) {
+	stages := []Stage{&BWT{BlockSize: 1000}, &BWT{}, &MoveToFront{}, &ZeroRunLength{}}
+	for _, stage := range stages {
+		for name, input := range testInputs() {
+			t.Run(stage.Name()+"/"+name, func(t *testing.T) {
+				forward, err := stage.Forward(input)
+				if err != nil {
+					t.Fatalf("Forward() error: %v", err)
+				}
+				inverse, err := stage.Inverse(forward)
+				if err != nil {
+					t.Fatalf("Inverse() error: %v", err)
+				}
+				if !bytes.Equal(inverse, input) {
+					t.Errorf("Round trip failed, expected %q, got %q", input, inverse)
+				}
+				if bound := stage.MaxForwardLength(uint64(len(input))); uint64(len(forward)) > bound {
+					t.Errorf("Expected at most %d bytes from Forward, got %d", bound, len(forward))
+				}
+			})
+		}
+	}
+}
+
+func TestBWTBanana(t *testing.T) {
+	// Sorted rotations of banana$: $banana, a$banan, ana$ban, anana$b, banana$, na$bana, nana$ba
+	lastColumn, primary := bwtBlock([]byte("banana"))
+	if string(lastColumn) != "annbaa" || primary != 4 {
+		t.Errorf("Expected annbaa with primary 4, got %s with primary %d", lastColumn, primary)
+	}
+}
+
+func TestSuffixArray(t *testing.T) {
+	for name, input := range testInputs() {
+		t.Run(name, func(t *testing.T) {
+			expected := make([]int, len(input))
+			for i := range expected {
+				expected[i] = i
+			}
+			sort.Slice(expected, func(i, j int) bool {
+				return bytes.Compare(input[expected[i]:], input[expected[j]:]) < 0
+			})
+
+			sa := suffixArray(input)
+			for i := range expected {
+				if sa[i] != expected[i] {
+					t.Fatalf("Expected suffix %d at rank %d, got %d", expected[i], i, sa[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMoveToFront(t *testing.T) {
+	forward, _ := (&MoveToFront{}).Forward([]byte("bbbaaa"))
+	expected := []byte{'b', 0, 0, 'b', 0, 0}
+	if !bytes.Equal(forward, expected) {
+		t.Errorf("Expected %v, got %v", expected, forward)
+	}
+}
+
+func TestZeroRunLength(t *testing.T) {
+	input := append(append([]byte{7}, make([]byte, 300)...), 9)
+	forward, _ := (&ZeroRunLength{}).Forward(input)
+	expected := []byte{7, 0, 255, 0, 43, 9}
+	if !bytes.Equal(forward, expected) {
+		t.Errorf("Expected %v, got %v", expected, forward)
+	}
+
+	if _, err := (&ZeroRunLength{}).Inverse([]byte{7, 0}); err == nil {
+		t.Error("Expected an error for a missing run length, got nil")
+	}
+}
+
+func TestParse(t *testing.T) {
+	p, err := Parse("bwt, mtf,zrle")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if p.String() != "bwt,mtf,zrle" || string(p.IDs()) != "BMZ" {
+		t.Errorf("Unexpected pipeline %s (%s)", p, p.IDs())
+	}
+
+	fromIDs, err := FromIDs(p.IDs())
+	if err != nil || fromIDs.String() != p.String() {
+		t.Errorf("Expected %s from IDs, got %s (%v)", p, fromIDs, err)
+	}
+
+	if _, err := Parse("bwt,nope"); err == nil {
+		t.Error("Expected an error for an unknown stage, got nil")
+	}
+	if _, err := FromIDs([]byte("?")); err == nil {
+		t.Error("Expected an error for an unknown stage id, got nil")
+	}
+}
+
+func TestPipelineShrinksText(t *testing.T) {
+	p, _ := Parse("bwt,mtf,zrle")
+	input := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog\n", 50))
+
+	forward, err := p.Forward(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(forward) >= len(input)/2 {
+		t.Errorf("Expected repetitive text to shrink below %d bytes, got %d", len(input)/2, len(forward))
+	}
+
+	inverse, err := p.Inverse(forward)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(inverse, input) {
+		t.Error("Pipeline round trip failed")
+	}
+}
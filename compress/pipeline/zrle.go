@@ -0,0 +1,64 @@
+package pipeline
+
+import (
+	"fmt"
+	"math"
+)
+
+// maxZeroRun is the longest run of zeros a single pair can hold.
+const maxZeroRun = 256
+
+// ZeroRunLength collapses runs of zero bytes, which dominate the output of MoveToFront.
+// A run is written as a zero byte followed by the run length minus one, other bytes are copied.
+type ZeroRunLength struct{}
+
+// ID implements Stage.
+func (z *ZeroRunLength) ID() byte { return 'Z' }
+
+// Name implements Stage.
+func (z *ZeroRunLength) Name() string { return "zrle" }
+
+// MaxForwardLength implements Stage. A lone zero doubles into a pair.
+func (z *ZeroRunLength) MaxForwardLength(n uint64) uint64 {
+	if n > math.MaxUint64/2 {
+		return math.MaxUint64
+	}
+	return 2 * n
+}
+
+// Forward replaces every run of up to 256 zeros by a zero and a count byte.
+func (z *ZeroRunLength) Forward(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); {
+		if data[i] != 0 {
+			out = append(out, data[i])
+			i++
+			continue
+		}
+
+		run := 1
+		for i+run < len(data) && data[i+run] == 0 && run < maxZeroRun {
+			run++
+		}
+		out = append(out, 0, byte(run-1))
+		i += run
+	}
+	return out, nil
+}
+
+// Inverse expands every zero and count pair back into a run of zeros.
+func (z *ZeroRunLength) Inverse(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	for i := 0; i < len(data); i++ {
+		if data[i] != 0 {
+			out = append(out, data[i])
+			continue
+		}
+		if i+1 == len(data) {
+			return nil, fmt.Errorf("missing run length at offset %d", i)
+		}
+		i++
+		out = append(out, make([]byte, int(data[i])+1)...)
+	}
+	return out, nil
+}
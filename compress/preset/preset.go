@@ -0,0 +1,159 @@
+package preset
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Farber98/cc-solutions/compress/pipeline"
+)
+
+// Level bounds.
+const (
+	MinLevel = 1
+	MaxLevel = 9
+)
+
+// Thresholds used by Auto.
+const (
+	// sampleSize is the size of each of the chunks Auto samples from the start, middle and end.
+	sampleSize = 32 << 10
+	// smallInput is the size below which a stored table or model costs more than it saves.
+	smallInput = 1 << 10
+	// incompressible is the order-0 entropy, in bits per byte, above which coding cannot gain much.
+	incompressible = 7.5
+	// repetitive is the share of the order-0 estimate that BWT has to beat to be worth its time.
+	repetitive = 0.8
+	// contextual is the share of the order-0 entropy the order-1 entropy has to beat.
+	contextual = 0.85
+)
+
+// Preset is a coder with the stages that run before it.
+type Preset struct {
+	// Coder is the name of the entropy coder, as given to compress -coder.
+	Coder string
+	// Context selects the order-1 context model of the range coder.
+	Context bool
+	// Stages run before the coder.
+	Stages pipeline.Pipeline
+}
+
+// String describes the preset with the flags that select it, e.g. "-coder range -stages bwt,mtf,zrle".
+func (p Preset) String() string {
+	s := "-coder " + p.Coder
+	if p.Context {
+		s += " -context"
+	}
+	if len(p.Stages) > 0 {
+		s += " -stages " + p.Stages.String()
+	}
+	return s
+}
+
+// bzip returns the bzip2-style stages with a BWT of blockSize bytes.
+func bzip(blockSize int) pipeline.Pipeline {
+	return pipeline.Pipeline{&pipeline.BWT{BlockSize: blockSize}, &pipeline.MoveToFront{}, &pipeline.ZeroRunLength{}}
+}
+
+// levels holds the presets of levels 1 to 9, ordered by speed and ratio as measured on
+// tests/test.txt. Plain Huffman is not among them: the static range coder is both faster and
+// smaller. The BWT block size is stored with every block, so decoding needs no level. Stages
+// hold no state, so presets can be shared.
+var levels = [MaxLevel + 1]Preset{
+	1: {Coder: "range"},
+	2: {Coder: "range", Context: true},
+	3: {Coder: "range", Stages: bzip(50 * 1000)},
+	4: {Coder: "range", Stages: bzip(100 * 1000)},
+	5: {Coder: "range", Stages: bzip(300 * 1000)},
+	6: {Coder: "range", Stages: bzip(pipeline.DefaultBlockSize)},
+	7: {Coder: "range", Context: true, Stages: bzip(pipeline.DefaultBlockSize)},
+	8: {Coder: "range", Context: true, Stages: bzip(2 * 1000 * 1000)},
+	9: {Coder: "range", Context: true, Stages: bzip(4 * 1000 * 1000)},
+}
+
+// Level returns the preset of a level from MinLevel, the fastest, to MaxLevel, the smallest.
+func Level(n int) (Preset, error) {
+	if n < MinLevel || n > MaxLevel {
+		return Preset{}, fmt.Errorf("invalid level: %d", n)
+	}
+	return levels[n], nil
+}
+
+// Auto picks a preset for data from a sample of it:
+//   - small inputs use the adaptive context model, which stores no table;
+//   - inputs close to random use the fast static range coder;
+//   - repetitive inputs, where BWT beats the order-0 estimate of the sample, use level 7;
+//   - the rest use the range coder, with the context model when the order-1 entropy is low.
+func Auto(data []byte) Preset {
+	if len(data) < smallInput {
+		return Preset{Coder: "range", Context: true}
+	}
+
+	s := sample(data)
+	h0 := entropy(s)
+	if h0 >= incompressible {
+		return Preset{Coder: "range"}
+	}
+
+	// Estimate BWT by the order-0 entropy of the transformed sample
+	transformed, err := bzip(len(s)).Forward(s)
+	if err == nil && entropy(transformed)*float64(len(transformed)) < repetitive*h0*float64(len(s)) {
+		return levels[7]
+	}
+
+	return Preset{Coder: "range", Context: conditionalEntropy(s) < contextual*h0}
+}
+
+// sample returns data when it is small, or chunks from its start, middle and end.
+func sample(data []byte) []byte {
+	if len(data) <= 3*sampleSize {
+		return data
+	}
+	middle := len(data)/2 - sampleSize/2
+	s := make([]byte, 0, 3*sampleSize)
+	s = append(s, data[:sampleSize]...)
+	s = append(s, data[middle:middle+sampleSize]...)
+	return append(s, data[len(data)-sampleSize:]...)
+}
+
+// entropy returns the order-0 entropy of data in bits per byte.
+func entropy(data []byte) float64 {
+	var counts [256]int
+	for _, b := range data {
+		counts[b]++
+	}
+	return entropyOf(counts[:], len(data))
+}
+
+// conditionalEntropy returns the order-1 entropy of data in bits per byte: the entropy of every
+// byte given the byte before it.
+func conditionalEntropy(data []byte) float64 {
+	if len(data) < 2 {
+		return 0
+	}
+	counts := make([][256]int, 256)
+	var totals [256]int
+	for i := 1; i < len(data); i++ {
+		counts[data[i-1]][data[i]]++
+		totals[data[i-1]]++
+	}
+
+	h := 0.0
+	for prev := range counts {
+		if totals[prev] > 0 {
+			h += float64(totals[prev]) * entropyOf(counts[prev][:], totals[prev])
+		}
+	}
+	return h / float64(len(data)-1)
+}
+
+// entropyOf returns the entropy in bits of the distribution given by counts summing to total.
+func entropyOf(counts []int, total int) float64 {
+	h := 0.0
+	for _, count := range counts {
+		if count > 0 {
+			p := float64(count) / float64(total)
+			h -= p * math.Log2(p)
+		}
+	}
+	return h
+}
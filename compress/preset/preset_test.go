@@ -0,0 +1,80 @@
+package preset
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestLevel(t *testing.T) {
+	for n := MinLevel; n <= MaxLevel; n++ {
+		p, err := Level(n)
+		if err != nil {
+			t.Fatalf("Level(%d) error: %v", n, err)
+		}
+		if p.Coder == "" {
+			t.Errorf("Level(%d) has no coder", n)
+		}
+	}
+
+	for _, n := range []int{0, 10, -1} {
+		if _, err := Level(n); err == nil {
+			t.Errorf("Expected an error for level %d", n)
+		}
+	}
+}
+
+func TestAuto(t *testing.T) {
+	random := make([]byte, 64<<10)
+	rand.New(rand.NewSource(43)).Read(random)
+
+	var logs bytes.Buffer
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&logs, "2024-01-01T00:00:%02d GET /api/items/%d 200\n", i%60, i%50)
+	}
+
+	// Independent bytes from a skewed distribution: nothing to gain from context or BWT
+	skewed := make([]byte, 64<<10)
+	r := rand.New(rand.NewSource(44))
+	for i := range skewed {
+		skewed[i] = byte(r.ExpFloat64() * 4)
+	}
+
+	testCases := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{name: "Small", data: []byte("key = value\n"), expected: "-coder range -context"},
+		{name: "Random", data: random, expected: "-coder range"},
+		{name: "Repetitive", data: logs.Bytes(), expected: "-coder range -context -stages bwt,mtf,zrle"},
+		{name: "Skewed", data: skewed, expected: "-coder range"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Auto(tc.data).String(); got != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSample(t *testing.T) {
+	data := make([]byte, 10*sampleSize)
+	for i := range data {
+		data[i] = byte(i / sampleSize)
+	}
+
+	s := sample(data)
+	if len(s) != 3*sampleSize {
+		t.Fatalf("Expected %d sampled bytes, got %d", 3*sampleSize, len(s))
+	}
+	if s[0] != 0 || s[len(s)-1] != 9 {
+		t.Errorf("Expected the sample to cover the start and the end")
+	}
+	if small := data[:100]; !bytes.Equal(sample(small), small) {
+		t.Errorf("Expected small data to be sampled whole")
+	}
+}
@@ -2,13 +2,17 @@
 
 package priority_queue
 
-import "container/heap"
+import (
+	"container/heap"
+	"sort"
+)
 
-// Node represents a node in the Huffman tree with character, frequency, and priority.
+// Node represents a node in the Huffman tree with symbol, frequency, and priority.
 type Node struct {
-	Char     byte  // Character
+	Symbol   int   // Symbol. A byte value, or an index into a larger alphabet such as a vocabulary.
 	Freq     int   // Frequency
 	Priority int   // Priority. Less freq has higher priority.
+	Order    int   // Tie-breaker between equal priorities. Lower order has higher priority.
 	Index    int   // Index in the heap
 	Left     *Node // Left child
 	Right    *Node // Right child
@@ -20,10 +24,14 @@ type PriorityQueue []*Node
 // Len returns the length of the priority queue.
 func (pq PriorityQueue) Len() int { return len(pq) }
 
-// Less compares two nodes by priority.
+// Less compares two nodes by priority, then by order, so that equal priorities always pop in
+// the same order and the same frequencies always build the same tree.
 func (pq PriorityQueue) Less(i, j int) bool {
 	// We want Pop to give us the lowest, so we use less than here.
-	return pq[i].Priority < pq[j].Priority
+	if pq[i].Priority != pq[j].Priority {
+		return pq[i].Priority < pq[j].Priority
+	}
+	return pq[i].Order < pq[j].Order
 }
 
 // Swap swaps two nodes in the priority queue.
@@ -51,14 +59,32 @@ func (pq *PriorityQueue) Pop() interface{} {
 }
 
 // NewPriorityQueue creates a new priority queue initialized with nodes for each character frequency.
+// Nodes are ordered by character, so nodes merged later should use orders from len(frequencies) on.
 func NewPriorityQueue(frequencies map[byte]int) PriorityQueue {
-	pq := make(PriorityQueue, 0)
-
+	symbols := make(map[int]int, len(frequencies))
 	for char, freq := range frequencies {
+		symbols[int(char)] = freq
+	}
+	return NewSymbolPriorityQueue(symbols)
+}
+
+// NewSymbolPriorityQueue creates a new priority queue initialized with nodes for each symbol frequency.
+// Nodes are ordered by symbol, so nodes merged later should use orders from len(frequencies) on.
+func NewSymbolPriorityQueue(frequencies map[int]int) PriorityQueue {
+	// Map iteration order is random, walk the symbols in ascending order instead
+	symbols := make([]int, 0, len(frequencies))
+	for symbol := range frequencies {
+		symbols = append(symbols, symbol)
+	}
+	sort.Ints(symbols)
+
+	pq := make(PriorityQueue, 0, len(symbols))
+	for _, symbol := range symbols {
 		node := &Node{
-			Char:     char,
-			Freq:     freq,
-			Priority: freq,
+			Symbol:   symbol,
+			Freq:     frequencies[symbol],
+			Priority: frequencies[symbol],
+			Order:    pq.Len(),
 		}
 		heap.Push(&pq, node)
 	}
@@ -38,3 +38,46 @@ func TestPriorityQueueLen(t *testing.T) {
 		t.Errorf("Expected length %d, got %d", len(frequencies), pq.Len())
 	}
 }
+
+func TestPriorityQueueTieBreaking(t *testing.T) {
+	frequencies := map[byte]int{
+		'd': 1,
+		'b': 2,
+		'a': 1,
+		'c': 2,
+		'e': 1,
+	}
+
+	// Run several times, map iteration order changes between runs
+	for run := 0; run < 20; run++ {
+		pq := NewPriorityQueue(frequencies)
+
+		// Equal frequencies pop in ascending character order
+		expectedChars := []byte{'a', 'd', 'e', 'b', 'c'}
+		for _, char := range expectedChars {
+			item := heap.Pop(&pq).(*Node)
+			if item.Symbol != int(char) {
+				t.Fatalf("Expected char %c, got %c", char, item.Symbol)
+			}
+		}
+	}
+}
+
+func TestSymbolPriorityQueue(t *testing.T) {
+	// Symbols beyond the byte range, e.g. vocabulary indices
+	frequencies := map[int]int{
+		1000: 5,
+		300:  1,
+		7:    5,
+		256:  1,
+	}
+	pq := NewSymbolPriorityQueue(frequencies)
+
+	expectedSymbols := []int{256, 300, 7, 1000}
+	for _, symbol := range expectedSymbols {
+		item := heap.Pop(&pq).(*Node)
+		if item.Symbol != symbol {
+			t.Errorf("Expected symbol %d, got %d", symbol, item.Symbol)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package range_coder
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Model maps byte symbols to frequency ranges for the range coder.
+type Model interface {
+	// Range returns the cumulative frequency, frequency and total of symbol after the previous byte.
+	Range(prev, symbol byte) (cum, freq, total uint32)
+	// Total returns the total frequency used after the previous byte.
+	Total(prev byte) uint32
+	// Find returns the symbol owning the cumulative frequency value after the previous byte.
+	Find(prev byte, value uint32) (symbol byte, cum, freq uint32)
+	// Update records that symbol followed the previous byte.
+	Update(prev, symbol byte)
+}
+
+// StaticModel is an order-0 model built from the byte frequencies of the whole input.
+// It must be stored alongside the encoded data.
+type StaticModel struct {
+	freqs [256]uint32
+	cums  [257]uint32
+}
+
+// NewStaticModel creates an order-0 model from byte frequencies, scaling them down to fit MaxTotal.
+func NewStaticModel(frequencies map[byte]int) *StaticModel {
+	m := &StaticModel{}
+
+	sum := 0
+	for _, freq := range frequencies {
+		sum += freq
+	}
+
+	// Leave room for every symbol keeping a frequency of at least one after scaling
+	limit := MaxTotal - 256
+	for char, freq := range frequencies {
+		scaled := freq
+		if sum > limit {
+			scaled = freq * limit / sum
+		}
+		if scaled == 0 && freq > 0 {
+			scaled = 1
+		}
+		m.freqs[char] = uint32(scaled)
+	}
+
+	m.accumulate()
+	return m
+}
+
+// accumulate computes the cumulative frequencies.
+func (m *StaticModel) accumulate() {
+	for i := 0; i < 256; i++ {
+		m.cums[i+1] = m.cums[i] + m.freqs[i]
+	}
+}
+
+// Range implements Model.
+func (m *StaticModel) Range(prev, symbol byte) (uint32, uint32, uint32) {
+	return m.cums[symbol], m.freqs[symbol], m.cums[256]
+}
+
+// Total implements Model.
+func (m *StaticModel) Total(prev byte) uint32 {
+	return m.cums[256]
+}
+
+// Find implements Model.
+func (m *StaticModel) Find(prev byte, value uint32) (byte, uint32, uint32) {
+	lo, hi := 0, 255
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if m.cums[mid] <= value {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return byte(lo), m.cums[lo], m.freqs[lo]
+}
+
+// Update implements Model. The static model never changes.
+func (m *StaticModel) Update(prev, symbol byte) {}
+
+// WriteTo writes the frequency table as a symbol count followed by symbol and uvarint frequency pairs.
+func (m *StaticModel) WriteTo(w io.Writer) (int64, error) {
+	var buf []byte
+	count := 0
+	for char, freq := range m.freqs {
+		if freq > 0 {
+			count++
+			buf = append(buf, byte(char))
+			buf = binary.AppendUvarint(buf, uint64(freq))
+		}
+	}
+
+	buf = append(binary.AppendUvarint(nil, uint64(count)), buf...)
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+// ReadStaticModel reads a frequency table written by StaticModel.WriteTo.
+func ReadStaticModel(r io.Reader) (*StaticModel, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, fmt.Errorf("error reading model size: %w", err)
+	}
+	if count > 256 {
+		return nil, fmt.Errorf("invalid model size: %d", count)
+	}
+
+	m := &StaticModel{}
+	total := uint64(0)
+	for i := uint64(0); i < count; i++ {
+		char, err := br.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("error reading model symbol: %w", err)
+		}
+		freq, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("error reading model frequency: %w", err)
+		}
+		total += freq
+		if freq == 0 || total > MaxTotal {
+			return nil, fmt.Errorf("invalid model frequency for symbol %d", char)
+		}
+		m.freqs[char] = uint32(freq)
+	}
+
+	m.accumulate()
+	return m, nil
+}
+
+// Constants for the adaptive order-1 model.
+const (
+	adaptiveIncrement = 32
+	adaptiveLimit     = MaxTotal - adaptiveIncrement
+)
+
+// ContextModel is an adaptive order-1 model: every previous byte has its own frequency
+// table, which starts flat and learns as symbols are coded. Nothing has to be stored.
+type ContextModel struct {
+	freqs  [256][256]uint32
+	totals [256]uint32
+}
+
+// NewContextModel creates an order-1 model with flat frequencies in every context.
+func NewContextModel() *ContextModel {
+	m := &ContextModel{}
+	for ctx := range m.freqs {
+		for char := range m.freqs[ctx] {
+			m.freqs[ctx][char] = 1
+		}
+		m.totals[ctx] = 256
+	}
+	return m
+}
+
+// Range implements Model.
+func (m *ContextModel) Range(prev, symbol byte) (uint32, uint32, uint32) {
+	freqs := &m.freqs[prev]
+	cum := uint32(0)
+	for i := 0; i < int(symbol); i++ {
+		cum += freqs[i]
+	}
+	return cum, freqs[symbol], m.totals[prev]
+}
+
+// Total implements Model.
+func (m *ContextModel) Total(prev byte) uint32 {
+	return m.totals[prev]
+}
+
+// Find implements Model.
+func (m *ContextModel) Find(prev byte, value uint32) (byte, uint32, uint32) {
+	freqs := &m.freqs[prev]
+	cum := uint32(0)
+	for i := 0; i < 255; i++ {
+		if cum+freqs[i] > value {
+			return byte(i), cum, freqs[i]
+		}
+		cum += freqs[i]
+	}
+	return 255, cum, freqs[255]
+}
+
+// Update implements Model, halving the context frequencies when the total gets too large.
+func (m *ContextModel) Update(prev, symbol byte) {
+	freqs := &m.freqs[prev]
+	freqs[symbol] += adaptiveIncrement
+	m.totals[prev] += adaptiveIncrement
+
+	if m.totals[prev] > adaptiveLimit {
+		total := uint32(0)
+		for i := range freqs {
+			freqs[i] = (freqs[i] + 1) / 2
+			total += freqs[i]
+		}
+		m.totals[prev] = total
+	}
+}
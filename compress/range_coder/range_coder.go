@@ -0,0 +1,139 @@
+package range_coder
+
+import (
+	"bufio"
+	"io"
+)
+
+// Constants for the coder precision.
+const (
+	// MaxTotal is the largest total frequency a model may use for a single symbol.
+	MaxTotal = 1 << 16
+	topValue = 1 << 24
+)
+
+// Encoder is a byte oriented range encoder with carry propagation.
+type Encoder struct {
+	w         *bufio.Writer
+	low       uint64
+	rng       uint32
+	cache     byte
+	cacheSize int64
+	err       error
+}
+
+// NewEncoder creates a new range encoder on top of w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:         bufio.NewWriter(w),
+		rng:       0xFFFFFFFF,
+		cacheSize: 1,
+	}
+}
+
+// Encode narrows the range to the symbol occupying [cum, cum+freq) out of total.
+func (e *Encoder) Encode(cum, freq, total uint32) error {
+	r := e.rng / total
+	e.low += uint64(cum) * uint64(r)
+	e.rng = r * freq
+	for e.rng < topValue {
+		e.rng <<= 8
+		e.shiftLow()
+	}
+	return e.err
+}
+
+// Flush writes the remaining state and flushes the underlying writer.
+func (e *Encoder) Flush() error {
+	for i := 0; i < 5; i++ {
+		e.shiftLow()
+	}
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// shiftLow outputs the top byte of low, holding back 0xFF bytes until a carry is resolved.
+func (e *Encoder) shiftLow() {
+	if uint32(e.low) < 0xFF000000 || e.low>>32 != 0 {
+		carry := byte(e.low >> 32)
+		temp := e.cache
+		for {
+			e.writeByte(temp + carry)
+			temp = 0xFF
+			e.cacheSize--
+			if e.cacheSize == 0 {
+				break
+			}
+		}
+		e.cache = byte(e.low >> 24)
+	}
+	e.cacheSize++
+	e.low = (e.low & 0x00FFFFFF) << 8
+}
+
+// writeByte writes b, remembering the first error.
+func (e *Encoder) writeByte(b byte) {
+	if e.err == nil {
+		e.err = e.w.WriteByte(b)
+	}
+}
+
+// Decoder is the range decoder matching Encoder.
+type Decoder struct {
+	r    io.ByteReader
+	code uint32
+	rng  uint32
+	step uint32
+}
+
+// NewDecoder creates a new range decoder on top of r and reads the initial state.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+
+	d := &Decoder{r: br, rng: 0xFFFFFFFF}
+	for i := 0; i < 5; i++ {
+		b, err := br.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		d.code = d.code<<8 | uint32(b)
+	}
+	return d, nil
+}
+
+// GetFreq returns the cumulative frequency the next symbol falls into. It must be
+// followed by Decode with the bounds of the symbol that owns that frequency.
+func (d *Decoder) GetFreq(total uint32) uint32 {
+	d.step = d.rng / total
+	value := d.code / d.step
+	if value >= total {
+		value = total - 1
+	}
+	return value
+}
+
+// Decode consumes the symbol occupying [cum, cum+freq).
+func (d *Decoder) Decode(cum, freq uint32) error {
+	d.code -= cum * d.step
+	d.rng = d.step * freq
+	for d.rng < topValue {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		d.code = d.code<<8 | uint32(b)
+		d.rng <<= 8
+	}
+	return nil
+}
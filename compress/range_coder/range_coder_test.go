@@ -0,0 +1,88 @@
+package range_coder
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncoderDecoderRoundTrip(t *testing.T) {
+	// Three symbols with frequencies 1, 6 and 1 out of 8
+	cums := []uint32{0, 1, 7}
+	freqs := []uint32{1, 6, 1}
+	symbols := []int{1, 1, 0, 1, 2, 1, 1, 1, 2, 0, 1}
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf)
+	for _, s := range symbols {
+		if err := e.Encode(cums[s], freqs[s], 8); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := e.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := NewDecoder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range symbols {
+		value := d.GetFreq(8)
+		s := 0
+		for s < 2 && cums[s+1] <= value {
+			s++
+		}
+		if s != expected {
+			t.Fatalf("Symbol %d: expected %d, got %d", i, expected, s)
+		}
+		if err := d.Decode(cums[s], freqs[s]); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestStaticModelSerialization(t *testing.T) {
+	m := NewStaticModel(map[byte]int{'a': 100000, 'b': 3, 'c': 1})
+	if m.Total(0) > MaxTotal {
+		t.Errorf("Expected total at most %d, got %d", MaxTotal, m.Total(0))
+	}
+	if _, freq, _ := m.Range(0, 'c'); freq == 0 {
+		t.Error("Expected rare symbol to keep a non zero frequency")
+	}
+
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadStaticModel(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.freqs != m.freqs {
+		t.Errorf("Expected frequencies %v, got %v", m.freqs, read.freqs)
+	}
+
+	for _, char := range []byte("abc") {
+		cum, freq, _ := m.Range(0, char)
+		found, foundCum, foundFreq := m.Find(0, cum+freq-1)
+		if found != char || foundCum != cum || foundFreq != freq {
+			t.Errorf("Find() returned %c for the range of %c", found, char)
+		}
+	}
+}
+
+func TestContextModelRescales(t *testing.T) {
+	m := NewContextModel()
+	for i := 0; i < 10000; i++ {
+		m.Update('q', 'u')
+	}
+	if m.Total('q') > MaxTotal {
+		t.Errorf("Expected total at most %d, got %d", MaxTotal, m.Total('q'))
+	}
+	if _, freq, _ := m.Range('q', 'x'); freq == 0 {
+		t.Error("Expected unseen symbol to keep a non zero frequency")
+	}
+	if _, freq, total := m.Range('q', 'u'); freq*2 < total {
+		t.Errorf("Expected 'u' to dominate the 'q' context, got %d of %d", freq, total)
+	}
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"bytes"
+	"math"
+	"sort"
+	"strconv"
+
+	compress "github.com/Farber98/cc-solutions/compress/compression"
+	"github.com/Farber98/cc-solutions/compress/container"
+	"github.com/Farber98/cc-solutions/compress/frequency"
+)
+
+// Symbol holds the statistics of a single byte value.
+type Symbol struct {
+	Byte byte `json:"byte"`
+	// Display is the byte as a quoted Go string, so non-printable bytes stay readable.
+	Display string  `json:"display"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+	Code    string  `json:"code"`
+}
+
+// Report describes how well data compresses with static Huffman coding.
+type Report struct {
+	Size     int `json:"size"`
+	Distinct int `json:"distinct"`
+	// Entropy is the Shannon entropy of the byte distribution in bits per byte.
+	Entropy float64 `json:"entropy"`
+	// AverageCodeLength is the average Huffman code length in bits per byte.
+	AverageCodeLength float64 `json:"average_code_length"`
+	// TheoreticalSize is the entropy bound in bytes, no order-0 coder can do better.
+	TheoreticalSize int `json:"theoretical_size"`
+	// PayloadSize is the size of the Huffman coded data in bytes.
+	PayloadSize int `json:"payload_size"`
+	// HeaderSize is the overhead of the frame header, the length and the code table in bytes.
+	HeaderSize int `json:"header_size"`
+	// AchievedSize is the size of the compressed file, PayloadSize plus HeaderSize.
+	AchievedSize int `json:"achieved_size"`
+	// Ratio is AchievedSize divided by Size.
+	Ratio float64 `json:"ratio"`
+	// Worthwhile reports whether the compressed file is smaller than the original.
+	Worthwhile bool `json:"worthwhile"`
+	// Symbols is sorted by count, most frequent first, then by byte value.
+	Symbols []Symbol `json:"symbols"`
+}
+
+// Analyzer defines the interface for computing compression statistics.
+type Analyzer interface {
+	Analyze(contents []byte) Report
+}
+
+// DefaultAnalyzer implements the Analyzer interface for the static Huffman coder.
+type DefaultAnalyzer struct{}
+
+// Analyze computes the statistics of contents.
+func (a *DefaultAnalyzer) Analyze(contents []byte) Report {
+	calculator := &frequency.DefaultCalculator{}
+	frequencies := calculator.CalculateFrequencies(contents)
+	codes := compress.BuildCodes(contents)
+
+	report := Report{Size: len(contents), Distinct: len(frequencies), Symbols: []Symbol{}}
+	payloadBits := 0
+	for char, freq := range frequencies {
+		p := float64(freq) / float64(len(contents))
+		report.Entropy -= p * math.Log2(p)
+		payloadBits += freq * len(codes[char])
+		report.Symbols = append(report.Symbols, Symbol{
+			Byte:    char,
+			Display: Display(char),
+			Count:   freq,
+			Percent: p * 100,
+			Code:    codes[char],
+		})
+	}
+	sort.Slice(report.Symbols, func(i, j int) bool {
+		if report.Symbols[i].Count != report.Symbols[j].Count {
+			return report.Symbols[i].Count > report.Symbols[j].Count
+		}
+		return report.Symbols[i].Byte < report.Symbols[j].Byte
+	})
+
+	// The achieved size is measured on a real frame, header included
+	payload := (&compress.HuffmanCompressor{}).Encode(contents, codes)
+	var frame bytes.Buffer
+	container.WriteHeader(&frame, container.Header{
+		Method:      container.MethodHuffman,
+		Length:      uint64(len(contents)),
+		Checksum:    container.Checksum(contents),
+		Sized:       true,
+		PayloadSize: uint64(len(payload)),
+	})
+	frame.Write(payload)
+
+	report.PayloadSize = (payloadBits + 7) / 8
+	report.AchievedSize = frame.Len()
+	report.HeaderSize = report.AchievedSize - report.PayloadSize
+	report.TheoreticalSize = int(math.Ceil(report.Entropy * float64(len(contents)) / 8))
+	if len(contents) > 0 {
+		report.AverageCodeLength = float64(payloadBits) / float64(len(contents))
+		report.Ratio = float64(report.AchievedSize) / float64(len(contents))
+	}
+	report.Worthwhile = report.AchievedSize < report.Size
+	return report
+}
+
+// Display returns char as a quoted Go string, escaping non-printable bytes, e.g. "a", "\n" or "\x00".
+func Display(char byte) string {
+	return strconv.Quote(string([]byte{char}))
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestDefaultAnalyzer_Analyze(t *testing.T) {
+	allBytes := make([]byte, 256)
+	for i := range allBytes {
+		allBytes[i] = byte(i)
+	}
+
+	testCases := []struct {
+		name              string
+		contents          []byte
+		entropy           float64
+		averageCodeLength float64
+		theoreticalSize   int
+		payloadSize       int
+		first             string
+	}{
+		{name: "Empty", contents: []byte{}},
+		{name: "SingleSymbol", contents: bytes.Repeat([]byte{0}, 16), averageCodeLength: 1, payloadSize: 2, first: `"\x00"`},
+		{name: "Skewed", contents: []byte("aaaaaaab"), entropy: 0.5436, averageCodeLength: 1, theoreticalSize: 1, payloadSize: 1, first: `"a"`},
+		{name: "Mixed", contents: []byte("abbcaabbccc"), entropy: 1.5726, averageCodeLength: 1.6364, theoreticalSize: 3, payloadSize: 3, first: `"b"`},
+		{name: "AllBytes", contents: allBytes, entropy: 8, averageCodeLength: 8, theoreticalSize: 256, payloadSize: 256, first: `"\x00"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			analyzer := &DefaultAnalyzer{}
+			report := analyzer.Analyze(tc.contents)
+
+			if math.Abs(report.Entropy-tc.entropy) > 1e-4 {
+				t.Errorf("Expected entropy %.4f, got %.4f", tc.entropy, report.Entropy)
+			}
+			if math.Abs(report.AverageCodeLength-tc.averageCodeLength) > 1e-4 {
+				t.Errorf("Expected average code length %.4f, got %.4f", tc.averageCodeLength, report.AverageCodeLength)
+			}
+			if report.TheoreticalSize != tc.theoreticalSize {
+				t.Errorf("Expected theoretical size %d, got %d", tc.theoreticalSize, report.TheoreticalSize)
+			}
+			if report.PayloadSize != tc.payloadSize {
+				t.Errorf("Expected payload size %d, got %d", tc.payloadSize, report.PayloadSize)
+			}
+			if report.AchievedSize != report.PayloadSize+report.HeaderSize || report.HeaderSize <= 0 {
+				t.Errorf("Expected achieved size to be payload plus header, got %d, %d and %d", report.AchievedSize, report.PayloadSize, report.HeaderSize)
+			}
+			if tc.first != "" && report.Symbols[0].Display != tc.first {
+				t.Errorf("Expected most frequent symbol %s, got %s", tc.first, report.Symbols[0].Display)
+			}
+		})
+	}
+}
+
+func TestDefaultAnalyzer_SortedByCount(t *testing.T) {
+	analyzer := &DefaultAnalyzer{}
+	report := analyzer.Analyze([]byte("cbbaaaddd\n"))
+
+	expected := []string{`"a"`, `"d"`, `"b"`, `"\n"`, `"c"`}
+	if len(report.Symbols) != len(expected) {
+		t.Fatalf("Expected %d symbols, got %d", len(expected), len(report.Symbols))
+	}
+	for i, symbol := range report.Symbols {
+		if symbol.Display != expected[i] {
+			t.Errorf("Expected symbol %d to be %s, got %s", i, expected[i], symbol.Display)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package token
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/Farber98/cc-solutions/compress/huffman"
+)
+
+// Limits on what ReadVocabulary accepts, so corrupt data cannot trigger huge allocations.
+const (
+	maxVocabularySize = 1 << 24
+	maxTokenLength    = 1 << 20
+	maxCodeLength     = 255
+)
+
+// Tokenize splits text into alternating words and separators. A word is a maximal run of ASCII
+// letters, digits and non-ASCII bytes, so UTF-8 encoded words stay whole; a separator is a
+// maximal run of any other bytes. Runs longer than the longest token ReadVocabulary accepts are
+// split. Joining the tokens gives back the text.
+func Tokenize(text []byte) [][]byte {
+	var tokens [][]byte
+	start := 0
+	for i := 1; i <= len(text); i++ {
+		if i == len(text) || isWordByte(text[i]) != isWordByte(text[start]) || i-start == maxTokenLength {
+			tokens = append(tokens, text[start:i])
+			start = i
+		}
+	}
+	return tokens
+}
+
+// isWordByte reports whether b belongs to a word.
+func isWordByte(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+// Vocabulary maps the distinct tokens of a text to symbols. Symbols are assigned by descending
+// count and then by token bytes, so the same text always gets the same vocabulary.
+type Vocabulary struct {
+	// Tokens holds the token of every symbol.
+	Tokens [][]byte
+	// Symbols holds the symbol of every token of the text, in order.
+	Symbols []int
+	// Frequencies holds the number of occurrences of every symbol.
+	Frequencies map[int]int
+}
+
+// NewVocabulary builds the vocabulary of tokens.
+func NewVocabulary(tokens [][]byte) *Vocabulary {
+	counts := make(map[string]int)
+	for _, t := range tokens {
+		counts[string(t)]++
+	}
+
+	distinct := make([]string, 0, len(counts))
+	for t := range counts {
+		distinct = append(distinct, t)
+	}
+	sort.Slice(distinct, func(i, j int) bool {
+		if counts[distinct[i]] != counts[distinct[j]] {
+			return counts[distinct[i]] > counts[distinct[j]]
+		}
+		return distinct[i] < distinct[j]
+	})
+
+	v := &Vocabulary{
+		Tokens:      make([][]byte, len(distinct)),
+		Symbols:     make([]int, len(tokens)),
+		Frequencies: make(map[int]int, len(distinct)),
+	}
+	index := make(map[string]int, len(distinct))
+	for symbol, t := range distinct {
+		v.Tokens[symbol] = []byte(t)
+		v.Frequencies[symbol] = counts[t]
+		index[t] = symbol
+	}
+	for i, t := range tokens {
+		v.Symbols[i] = index[string(t)]
+	}
+	return v
+}
+
+// AppendVocabulary appends the vocabulary header to buf: a uvarint entry count followed by the
+// uvarint token length, the token bytes, the uvarint code length and the code bits packed MSB
+// first for every symbol in order.
+func AppendVocabulary(buf []byte, tokens [][]byte, codes map[int]string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(tokens)))
+	for symbol, t := range tokens {
+		code := codes[symbol]
+		buf = binary.AppendUvarint(buf, uint64(len(t)))
+		buf = append(buf, t...)
+		buf = binary.AppendUvarint(buf, uint64(len(code)))
+		buf = huffman.AppendPackedCode(buf, code)
+	}
+	return buf
+}
+
+// Reader is what ReadVocabulary reads from.
+type Reader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// ReadVocabulary reads a header written by AppendVocabulary and returns the tokens together
+// with the reverse lookup table from codes to symbols.
+func ReadVocabulary(r Reader) ([][]byte, map[string]int, error) {
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading vocabulary size: %w", err)
+	}
+	if count > maxVocabularySize {
+		return nil, nil, fmt.Errorf("invalid vocabulary size: %d", count)
+	}
+
+	var tokens [][]byte
+	codeTable := make(map[string]int)
+	for symbol := 0; uint64(symbol) < count; symbol++ {
+		length, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading vocabulary: %w", err)
+		}
+		if length == 0 || length > maxTokenLength {
+			return nil, nil, fmt.Errorf("invalid token length: %d", length)
+		}
+		t := make([]byte, length)
+		if _, err := io.ReadFull(r, t); err != nil {
+			return nil, nil, fmt.Errorf("error reading vocabulary: %w", err)
+		}
+
+		codeLength, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading vocabulary: %w", err)
+		}
+		if codeLength == 0 || codeLength > maxCodeLength {
+			return nil, nil, fmt.Errorf("invalid code length: %d", codeLength)
+		}
+		code, err := huffman.ReadPackedCode(r, int(codeLength))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading vocabulary: %w", err)
+		}
+		if _, ok := codeTable[code]; ok {
+			return nil, nil, fmt.Errorf("duplicate code in vocabulary: %s", code)
+		}
+
+		tokens = append(tokens, t)
+		codeTable[code] = symbol
+	}
+	return tokens, codeTable, nil
+}
@@ -0,0 +1,114 @@
+package token
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{name: "Empty", text: "", expected: nil},
+		{name: "Words", text: "the cat, the hat.", expected: []string{"the", " ", "cat", ", ", "the", " ", "hat", "."}},
+		{name: "Leading_separator", text: "\n\nHello42", expected: []string{"\n\n", "Hello42"}},
+		{name: "UTF-8", text: "café au lait", expected: []string{"café", " ", "au", " ", "lait"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, tok := range Tokenize([]byte(tc.text)) {
+				got = append(got, string(tok))
+			}
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestTokenizeJoins(t *testing.T) {
+	// Property: joining the tokens gives back the text
+	property := func(text []byte) bool {
+		return bytes.Equal(bytes.Join(Tokenize(text), nil), text)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTokenizeLongRuns(t *testing.T) {
+	text := append(bytes.Repeat([]byte{0}, 2*maxTokenLength+1), "word"...)
+	tokens := Tokenize(text)
+
+	lengths := make([]int, len(tokens))
+	for i, token := range tokens {
+		lengths[i] = len(token)
+	}
+	expected := []int{maxTokenLength, maxTokenLength, 1, 4}
+	if !reflect.DeepEqual(lengths, expected) {
+		t.Errorf("Expected token lengths %v, got %v", expected, lengths)
+	}
+}
+
+func TestNewVocabulary(t *testing.T) {
+	v := NewVocabulary(Tokenize([]byte("b a b a b c")))
+
+	expectedTokens := [][]byte{[]byte(" "), []byte("b"), []byte("a"), []byte("c")}
+	if !reflect.DeepEqual(v.Tokens, expectedTokens) {
+		t.Errorf("Expected tokens %q, got %q", expectedTokens, v.Tokens)
+	}
+	expectedSymbols := []int{1, 0, 2, 0, 1, 0, 2, 0, 1, 0, 3}
+	if !reflect.DeepEqual(v.Symbols, expectedSymbols) {
+		t.Errorf("Expected symbols %v, got %v", expectedSymbols, v.Symbols)
+	}
+	if v.Frequencies[0] != 5 || v.Frequencies[3] != 1 {
+		t.Errorf("Unexpected frequencies %v", v.Frequencies)
+	}
+}
+
+func TestVocabularyRoundTrip(t *testing.T) {
+	tokens := [][]byte{[]byte("the"), []byte(" "), []byte("\x00\xff")}
+	codes := map[int]string{0: "0", 1: "10", 2: "110000000001"}
+
+	tokensRead, codeTable, err := ReadVocabulary(bytes.NewReader(AppendVocabulary(nil, tokens, codes)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(tokensRead, tokens) {
+		t.Errorf("Expected tokens %q, got %q", tokens, tokensRead)
+	}
+	expected := map[string]int{"0": 0, "10": 1, "110000000001": 2}
+	if !reflect.DeepEqual(codeTable, expected) {
+		t.Errorf("Expected code table %v, got %v", expected, codeTable)
+	}
+}
+
+func TestReadVocabularyErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		contents      string
+		expectedError string
+	}{
+		{name: "Empty", contents: "", expectedError: "error reading vocabulary size"},
+		{name: "Truncated_token", contents: "\x01\x05ab", expectedError: "error reading vocabulary"},
+		{name: "Empty_token", contents: "\x01\x00", expectedError: "invalid token length"},
+		{name: "Empty_code", contents: "\x01\x01a\x00", expectedError: "invalid code length"},
+		{name: "Duplicate_code", contents: "\x02\x01a\x01\x00\x01b\x01\x00", expectedError: "duplicate code"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, err := ReadVocabulary(strings.NewReader(tc.contents))
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected error containing %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
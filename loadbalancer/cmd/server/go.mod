@@ -0,0 +1,8 @@
+module loadbalancer/cmd/server
+
+go 1.24.1
+
+require github.com/Farber98/cc-solutions/compress v0.0.0
+
+// The backends serve through the compress middleware, which is not published
+replace github.com/Farber98/cc-solutions/compress v0.0.0 => ../../../compress
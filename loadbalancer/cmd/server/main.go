@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+
+	"github.com/Farber98/cc-solutions/compress/httpencoding"
 )
 
 func main() {
@@ -16,5 +18,5 @@ func main() {
 	})
 
 	log.Printf("Server listening on %v", *port)
-	log.Fatal(http.ListenAndServe(":"+*port, nil))
+	log.Fatal(http.ListenAndServe(":"+*port, httpencoding.Handler(http.DefaultServeMux, httpencoding.DefaultConfig)))
 }
@@ -0,0 +1,15 @@
+module github.com/Farber98/cc-solutions/ratelimiter/cmd/server
+
+go 1.24.1
+
+require (
+	github.com/Farber98/cc-solutions/compress v0.0.0
+	github.com/Farber98/cc-solutions/ratelimiter v0.0.0
+)
+
+// The demo server serves through the compress middleware, which is not published, so it is a
+// module of its own and the ratelimit package does not depend on it
+replace (
+	github.com/Farber98/cc-solutions/compress v0.0.0 => ../../../compress
+	github.com/Farber98/cc-solutions/ratelimiter v0.0.0 => ../..
+)
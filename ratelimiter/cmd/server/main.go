@@ -3,14 +3,19 @@ package main
 import (
 	"encoding/json"
 	"log"
+	"math"
 	"net"
 	"net/http"
+	"strconv"
 	"time"
+
+	"github.com/Farber98/cc-solutions/compress/httpencoding"
+	"github.com/Farber98/cc-solutions/ratelimiter/ratelimit"
 )
 
 type RateLimiter interface {
-	Allow(ip string) bool
-	GetState(ip string) map[string]any
+	ratelimit.Limiter
+	GetState(key string) map[string]any
 }
 
 type app struct {
@@ -22,10 +27,10 @@ func newApp(rl RateLimiter) *app {
 }
 
 func main() {
-	//tb := NewTokenBucket(10, 5*time.Second, 5*time.Minute)
-	//fw := NewFixedWindow(3, 20*time.Second)
-	// slw := NewSlidingWindowLog(5, 10*time.Second)
-	slwc := NewSlidingWindowLog(3, 5*time.Second)
+	//tb := ratelimit.NewTokenBucket(10, 5*time.Second, ratelimit.WithIdleTimeout(5*time.Minute))
+	//fw := ratelimit.NewFixedWindow(3, 20*time.Second)
+	// slw := ratelimit.NewSlidingWindowLog(5, 10*time.Second)
+	slwc := ratelimit.NewSlidingWindowLog(3, 5*time.Second)
 	app := newApp(slwc)
 
 	http.HandleFunc("/limited", func(w http.ResponseWriter, r *http.Request) {
@@ -37,8 +42,9 @@ func main() {
 			return
 		}
 
-		if !app.rl.Allow(ip) {
+		if wait := app.rl.Reserve(ip); wait > 0 {
 			log.Println("Rate limit exceeded for /limited")
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
 			w.WriteHeader(http.StatusTooManyRequests)
 			w.Write([]byte("Rate limit exceeded!"))
 			return
@@ -65,5 +71,5 @@ func main() {
 		json.NewEncoder(w).Encode(state)
 	})
 
-	http.ListenAndServe(":8080", nil)
+	http.ListenAndServe(":8080", httpencoding.Handler(http.DefaultServeMux, httpencoding.DefaultConfig))
 }
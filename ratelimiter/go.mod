@@ -1,4 +1,4 @@
-module ratelimiter
+module github.com/Farber98/cc-solutions/ratelimiter
 
 go 1.24.1
 
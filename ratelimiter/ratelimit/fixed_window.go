@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FixedWindow allows size requests per key every window, and starts counting again when the
+// window of the key ends. Windows start with the first request of a key and roll over on its
+// next request, so keys without requests cost nothing and are dropped.
+type FixedWindow struct {
+	size    int
+	window  time.Duration
+	windows *keyStore[fixedWindow]
+	mu      sync.Mutex
+	now     func() time.Time
+}
+
+// fixedWindow is the window of a key.
+type fixedWindow struct {
+	count int
+	start time.Time
+}
+
+// NewFixedWindow returns a FixedWindow that allows size requests per key every window.
+func NewFixedWindow(size int, window time.Duration, opts ...Option) *FixedWindow {
+	c := newConfig(opts)
+	return &FixedWindow{
+		size:    size,
+		window:  window,
+		windows: newKeyStore[fixedWindow](window, c.maxKeys),
+		now:     c.now,
+	}
+}
+
+// advance moves w to the window of now, keeping the windows aligned to the first one.
+func (fw *FixedWindow) advance(w *fixedWindow, now time.Time) {
+	if w.start.IsZero() {
+		w.start = now
+		return
+	}
+	if elapsed := now.Sub(w.start); elapsed >= fw.window {
+		w.count = 0
+		w.start = w.start.Add(elapsed / fw.window * fw.window)
+	}
+}
+
+// take counts n requests of key and returns zero, or returns how long until the window of key
+// ends without counting them.
+func (fw *FixedWindow) take(key string, n int) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	if n > fw.size {
+		return never
+	}
+
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	now := fw.now()
+	w := fw.windows.get(key, now)
+	fw.advance(w, now)
+	if w.count+n > fw.size {
+		return w.start.Add(fw.window).Sub(now)
+	}
+	w.count += n
+	return 0
+}
+
+// Allow reports whether the window of key has room for a request, and counts it when it does.
+func (fw *FixedWindow) Allow(key string) bool {
+	return fw.take(key, 1) == 0
+}
+
+// AllowN reports whether the window of key has room for n requests, and counts them when it
+// does.
+func (fw *FixedWindow) AllowN(key string, n int) bool {
+	return fw.take(key, n) == 0
+}
+
+// Reserve counts a request of key and returns zero, or returns how long until its window ends.
+func (fw *FixedWindow) Reserve(key string) time.Duration {
+	return fw.take(key, 1)
+}
+
+// Wait blocks until the window of key has room for a request and counts it, or until ctx is
+// done.
+func (fw *FixedWindow) Wait(ctx context.Context, key string) error {
+	return wait(ctx, func() time.Duration { return fw.take(key, 1) })
+}
+
+// GetState returns the requests of key counted in its window and the size.
+func (fw *FixedWindow) GetState(key string) map[string]any {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+
+	now := fw.now()
+	count := 0
+	if w := fw.windows.peek(key, now); w != nil {
+		fw.advance(w, now)
+		count = w.count
+	}
+	return map[string]any{
+		"count": count,
+		"size":  fw.size,
+	}
+}
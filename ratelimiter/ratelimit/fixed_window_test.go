@@ -0,0 +1,109 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+)
+
+func TestFixedWindowCreation(t *testing.T) {
+	fw := NewFixedWindow(10, 1*time.Minute)
+	require.Equal(t, fw.windows.len(), 0)
+	require.Equal(t, fw.size, 10)
+}
+
+func TestAllowFw(t *testing.T) {
+	fw := NewFixedWindow(10, 1*time.Minute)
+	for range 10 {
+		require.True(t, fw.Allow(""))
+	}
+	require.False(t, fw.Allow(""))
+}
+
+func TestAllowNFw(t *testing.T) {
+	fw := NewFixedWindow(10, 1*time.Minute)
+	require.True(t, fw.AllowN("", 7))
+	require.False(t, fw.AllowN("", 4))
+	require.True(t, fw.AllowN("", 3))
+	require.False(t, fw.AllowN("", 11))
+}
+
+func TestReserveFw(t *testing.T) {
+	clock := newFakeClock()
+	fw := NewFixedWindow(2, 1*time.Minute, WithClock(clock.Now))
+	require.Zero(t, fw.Reserve(""))
+	require.Zero(t, fw.Reserve(""))
+
+	clock.Advance(20 * time.Second)
+	require.Equal(t, 40*time.Second, fw.Reserve(""))
+	require.Equal(t, 2, fw.GetState("")["count"])
+}
+
+func TestKeysFw(t *testing.T) {
+	clock := newFakeClock()
+	fw := NewFixedWindow(3, 1*time.Minute, WithClock(clock.Now))
+
+	// Key A exhausting its quota does not affect key B
+	require.True(t, fw.AllowN("A", 3))
+	require.False(t, fw.Allow("A"))
+	for range 3 {
+		require.True(t, fw.Allow("B"))
+	}
+	require.False(t, fw.Allow("B"))
+
+	// Every key has its own window, starting with its first request
+	clock.Advance(30 * time.Second)
+	require.True(t, fw.AllowN("C", 3))
+	clock.Advance(30 * time.Second)
+	require.True(t, fw.AllowN("A", 3))
+	require.Equal(t, 30*time.Second, fw.Reserve("C"))
+	require.Equal(t, 0, fw.GetState("B")["count"])
+	require.Equal(t, 3, fw.GetState("C")["count"])
+}
+
+func TestGetStateFw(t *testing.T) {
+	fw := NewFixedWindow(10, 1*time.Minute)
+	fw.Allow("")
+	mp := fw.GetState("")
+	require.Equal(t, mp["size"], 10)
+	require.Equal(t, mp["count"], 1)
+}
+
+func TestRolloverFw(t *testing.T) {
+	clock := newFakeClock()
+	fw := NewFixedWindow(10, 1*time.Second, WithClock(clock.Now))
+	for range 10 {
+		fw.Allow("")
+	}
+	clock.Advance(1 * time.Second)
+	require.Equal(t, fw.GetState("")["count"], 0)
+
+	// Windows of a key in use stay aligned to its first one
+	require.True(t, fw.Allow(""))
+	clock.Advance(800 * time.Millisecond)
+	require.True(t, fw.AllowN("", 9))
+	clock.Advance(400 * time.Millisecond)
+	require.True(t, fw.AllowN("", 10))
+	require.Equal(t, 800*time.Millisecond, fw.Reserve(""))
+}
+
+func TestEvictFw(t *testing.T) {
+	clock := newFakeClock()
+	fw := NewFixedWindow(2, 1*time.Minute, WithClock(clock.Now), WithMaxKeys(2))
+	require.True(t, fw.AllowN("A", 2))
+	require.True(t, fw.AllowN("B", 2))
+
+	// Keys are dropped once their window is over
+	clock.Advance(1 * time.Minute)
+	require.True(t, fw.Allow("C"))
+	require.Equal(t, 1, fw.windows.len())
+
+	// Past the bound the least recently used key starts over
+	require.True(t, fw.AllowN("D", 2))
+	require.True(t, fw.Allow("C"))
+	require.True(t, fw.Allow("E"))
+	require.Equal(t, 2, fw.windows.len())
+	require.Equal(t, 0, fw.GetState("D")["count"])
+	require.Equal(t, 2, fw.GetState("C")["count"])
+}
@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"container/list"
+	"time"
+)
+
+// keyStore holds the state of every key in the order the keys were last used. Keys idle for
+// idle are dropped, unless idle is not positive, and so is the least recently used key when a
+// new one would go over maxKeys. It is not safe for concurrent use.
+type keyStore[T any] struct {
+	idle    time.Duration
+	maxKeys int
+	entries map[string]*list.Element
+	// The entries from the least to the most recently used
+	order *list.List
+}
+
+type keyEntry[T any] struct {
+	key      string
+	lastUsed time.Time
+	state    T
+}
+
+func newKeyStore[T any](idle time.Duration, maxKeys int) *keyStore[T] {
+	return &keyStore[T]{
+		idle:    idle,
+		maxKeys: maxKeys,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the state of key, a zero one for a new key, and marks key used at now.
+func (s *keyStore[T]) get(key string, now time.Time) *T {
+	s.evict(now)
+	if e, ok := s.entries[key]; ok {
+		entry := e.Value.(*keyEntry[T])
+		entry.lastUsed = now
+		s.order.MoveToBack(e)
+		return &entry.state
+	}
+
+	if s.maxKeys > 0 && len(s.entries) >= s.maxKeys {
+		s.remove(s.order.Front())
+	}
+	entry := &keyEntry[T]{key: key, lastUsed: now}
+	s.entries[key] = s.order.PushBack(entry)
+	return &entry.state
+}
+
+// peek returns the state of key without marking it used, or nil when key has none.
+func (s *keyStore[T]) peek(key string, now time.Time) *T {
+	s.evict(now)
+	if e, ok := s.entries[key]; ok {
+		return &e.Value.(*keyEntry[T]).state
+	}
+	return nil
+}
+
+// evict drops the keys idle for idle at now.
+func (s *keyStore[T]) evict(now time.Time) {
+	if s.idle <= 0 {
+		return
+	}
+	for e := s.order.Front(); e != nil; e = s.order.Front() {
+		if now.Sub(e.Value.(*keyEntry[T]).lastUsed) < s.idle {
+			return
+		}
+		s.remove(e)
+	}
+}
+
+func (s *keyStore[T]) remove(e *list.Element) {
+	delete(s.entries, e.Value.(*keyEntry[T]).key)
+	s.order.Remove(e)
+}
+
+// len returns the number of keys held.
+func (s *keyStore[T]) len() int {
+	return len(s.entries)
+}
@@ -0,0 +1,31 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+)
+
+func TestKeyStore(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := newKeyStore[int](1*time.Minute, 3)
+
+	*s.get("A", start) = 1
+	*s.get("B", start.Add(10*time.Second)) = 2
+	*s.get("C", start.Add(20*time.Second)) = 3
+	require.Equal(t, 1, *s.get("A", start.Add(30*time.Second)))
+
+	// Peeking does not count as a use, so B is the least recently used key
+	require.Equal(t, 2, *s.peek("B", start.Add(40*time.Second)))
+	require.Zero(t, *s.get("D", start.Add(40*time.Second)))
+	require.Nil(t, s.peek("B", start.Add(40*time.Second)))
+	require.Equal(t, 3, s.len())
+
+	// Keys idle for a minute are dropped
+	require.Nil(t, s.peek("C", start.Add(80*time.Second)))
+	require.Equal(t, 1, *s.peek("A", start.Add(80*time.Second)))
+	require.Equal(t, 2, s.len())
+	require.Nil(t, s.peek("A", start.Add(100*time.Second)))
+	require.Equal(t, 0, s.len())
+}
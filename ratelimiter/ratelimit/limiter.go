@@ -0,0 +1,102 @@
+// Package ratelimit limits the rate of requests per key, such as a client IP or an API token,
+// with the token bucket, fixed window, sliding window log and sliding window counter algorithms.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limiter decides whether the requests for a key are allowed. It is safe for concurrent use.
+type Limiter interface {
+	// Allow reports whether a request for key is allowed now, and counts it when it is.
+	Allow(key string) bool
+	// AllowN reports whether n requests for key are allowed now, and counts them all when they are.
+	AllowN(key string, n int) bool
+	// Reserve counts a request for key and returns zero when it is allowed now. Otherwise nothing
+	// is counted, and it returns how long until the request would be allowed.
+	Reserve(key string) time.Duration
+	// Wait blocks until a request for key is allowed and counts it. It fails with the error of
+	// ctx once ctx is done.
+	Wait(ctx context.Context, key string) error
+}
+
+var (
+	_ Limiter = (*TokenBucket)(nil)
+	_ Limiter = (*FixedWindow)(nil)
+	_ Limiter = (*SlidingWindowLog)(nil)
+	_ Limiter = (*SlidingWindowCounter)(nil)
+)
+
+// DefaultIdleTimeout is how long a key is kept without requests when WithIdleTimeout is not given.
+const DefaultIdleTimeout = 5 * time.Minute
+
+// DefaultMaxKeys is how many keys a TokenBucket, a FixedWindow, a SlidingWindowLog or a
+// SlidingWindowCounter holds when WithMaxKeys is not given.
+const DefaultMaxKeys = 100_000
+
+// never is the wait for requests that can never be allowed, as they are more than the limit.
+const never = time.Duration(math.MaxInt64)
+
+// Option configures a Limiter.
+type Option func(*config)
+
+// config holds the settings chosen with options.
+type config struct {
+	now         func() time.Time
+	idleTimeout time.Duration
+	maxKeys     int
+}
+
+// WithClock makes a Limiter read the time from now instead of time.Now, e.g. to test it.
+func WithClock(now func() time.Time) Option {
+	return func(c *config) { c.now = now }
+}
+
+// WithIdleTimeout sets how long a TokenBucket keeps the bucket of a key without requests,
+// DefaultIdleTimeout when not given. Idle buckets are dropped on the next request of any key, a
+// bucket that is dropped is full the next time, and buckets are never dropped when timeout is
+// not positive.
+func WithIdleTimeout(timeout time.Duration) Option {
+	return func(c *config) { c.idleTimeout = timeout }
+}
+
+// WithMaxKeys bounds the keys a TokenBucket, a FixedWindow, a SlidingWindowLog or a
+// SlidingWindowCounter holds, DefaultMaxKeys when not given. Windows and logs are dropped once
+// they are over, as they start over anyway, and the least recently used key is dropped to make
+// room for a new one past keys, which starts it over early. There is no bound when keys is not
+// positive.
+func WithMaxKeys(keys int) Option {
+	return func(c *config) { c.maxKeys = keys }
+}
+
+// newConfig applies the options over the defaults.
+func newConfig(opts []Option) config {
+	c := config{now: time.Now, idleTimeout: DefaultIdleTimeout, maxKeys: DefaultMaxKeys}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// wait calls reserve until it allows the request, sleeping for the wait it returns in between.
+func wait(ctx context.Context, reserve func() time.Duration) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		delay := reserve()
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+)
+
+// fakeClock is a clock for WithClock that only moves when told to.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// limiters returns every Limiter, allowing limit requests per window.
+func limiters(limit int, window time.Duration, opts ...Option) map[string]Limiter {
+	return map[string]Limiter{
+		"TokenBucket":          NewTokenBucket(limit, window/time.Duration(limit), opts...),
+		"FixedWindow":          NewFixedWindow(limit, window, opts...),
+		"SlidingWindowLog":     NewSlidingWindowLog(limit, window, opts...),
+		"SlidingWindowCounter": NewSlidingWindowCounter(limit, window, opts...),
+	}
+}
+
+func TestLimiter_Reserve(t *testing.T) {
+	for name, l := range limiters(4, 1*time.Minute, WithClock(newFakeClock().Now)) {
+		t.Run(name, func(t *testing.T) {
+			key := "192.168.1.1"
+			require.True(t, l.AllowN(key, 0))
+			require.False(t, l.AllowN(key, 5))
+			require.True(t, l.AllowN(key, 3))
+			require.False(t, l.AllowN(key, 2))
+			require.Zero(t, l.Reserve(key))
+
+			// Once the limit is reached nothing more is counted
+			wait := l.Reserve(key)
+			require.True(t, wait > 0)
+			require.True(t, wait <= 1*time.Minute+1)
+			require.Equal(t, wait, l.Reserve(key))
+			require.False(t, l.Allow(key))
+		})
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	for name, l := range limiters(2, 200*time.Millisecond) {
+		t.Run(name, func(t *testing.T) {
+			key := "192.168.1.1"
+			require.True(t, l.AllowN(key, 2))
+
+			canceled, cancel := context.WithCancel(context.Background())
+			cancel()
+			require.Equal(t, context.Canceled, l.Wait(canceled, key))
+
+			short, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			require.Equal(t, context.DeadlineExceeded, l.Wait(short, key))
+
+			start := time.Now()
+			require.NoError(t, l.Wait(context.Background(), key))
+			require.True(t, time.Since(start) < 1*time.Second)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// SlidingWindowCounter counts the requests of every key in fixed windows, and estimates the
+// requests of the last windowDuration by weighting the count of the previous window by how much
+// of it overlaps. Windows start with the first request of a key and roll over on its next
+// request, so keys without requests cost nothing and are dropped.
+type SlidingWindowCounter struct {
+	threshold      int
+	windowDuration time.Duration
+	windows        *keyStore[slidingWindow]
+	mu             sync.Mutex
+	now            func() time.Time
+}
+
+// slidingWindow is the current and previous windows of a key.
+type slidingWindow struct {
+	currentWindowCount  int
+	previousWindowCount int
+	currentWindowStart  time.Time
+}
+
+// NewSlidingWindowCounter returns a SlidingWindowCounter that allows threshold requests per key
+// in any span of windowDuration.
+func NewSlidingWindowCounter(threshold int, windowDuration time.Duration, opts ...Option) *SlidingWindowCounter {
+	c := newConfig(opts)
+	return &SlidingWindowCounter{
+		threshold:      threshold,
+		windowDuration: windowDuration,
+		// Two windows after its last request the counts of a key are zero
+		windows: newKeyStore[slidingWindow](2*windowDuration, c.maxKeys),
+		now:     c.now,
+	}
+}
+
+// advance moves w to the window of now and returns how far into it now is.
+func (swc *SlidingWindowCounter) advance(w *slidingWindow, now time.Time) time.Duration {
+	if w.currentWindowStart.IsZero() {
+		w.currentWindowStart = now
+	}
+	elapsed := now.Sub(w.currentWindowStart)
+	if elapsed < swc.windowDuration {
+		return elapsed
+	}
+
+	windows := elapsed / swc.windowDuration
+	w.previousWindowCount = w.currentWindowCount
+	if windows > 1 {
+		w.previousWindowCount = 0
+	}
+	w.currentWindowCount = 0
+	w.currentWindowStart = w.currentWindowStart.Add(windows * swc.windowDuration)
+	return elapsed - windows*swc.windowDuration
+}
+
+// take counts n requests of key and returns zero, or returns how long until the weighted count
+// of key leaves room for them without counting them.
+func (swc *SlidingWindowCounter) take(key string, n int) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	if n > swc.threshold {
+		return never
+	}
+
+	swc.mu.Lock()
+	defer swc.mu.Unlock()
+
+	now := swc.now()
+	w := swc.windows.get(key, now)
+	elapsed := swc.advance(w, now)
+	// Weighted sum: (1 - elapsedRatio) * previous + current
+	elapsedRatio := float64(elapsed) / float64(swc.windowDuration)
+	weightedCount := (1-elapsedRatio)*float64(w.previousWindowCount) + float64(w.currentWindowCount)
+
+	// The requests fit while the weighted count stays under the threshold before the last one
+	room := float64(swc.threshold - n + 1)
+	if weightedCount < room {
+		w.currentWindowCount += n
+		return 0
+	}
+	return swc.untilRoom(w, elapsed, room)
+}
+
+// untilRoom returns how long from elapsed into the current window of w until the weighted count
+// drops under room, if no more requests are counted.
+func (swc *SlidingWindowCounter) untilRoom(w *slidingWindow, elapsed time.Duration, room float64) time.Duration {
+	window := float64(swc.windowDuration)
+	// The previous window weighs less as the current one goes by
+	previous, current, start := float64(w.previousWindowCount), float64(w.currentWindowCount), 0.0
+	if current >= room {
+		// Nothing fits before the next window, where the current count becomes the previous one
+		previous, current, start = current, 0, window
+	}
+
+	// (1 - ratio) * previous + current < room once ratio passes 1 - (room - current) / previous
+	ratio := max(1-(room-current)/previous, 0)
+	wait := time.Duration(math.Floor(start+ratio*window)) + 1 - elapsed
+	return max(wait, 1)
+}
+
+// Allow reports whether the weighted count of key is under the threshold, and counts the
+// request when it is.
+func (swc *SlidingWindowCounter) Allow(key string) bool {
+	return swc.take(key, 1) == 0
+}
+
+// AllowN reports whether the weighted count of key leaves room for n requests, and counts them
+// when it does.
+func (swc *SlidingWindowCounter) AllowN(key string, n int) bool {
+	return swc.take(key, n) == 0
+}
+
+// Reserve counts a request of key and returns zero, or returns how long until the weighted
+// count of key is under the threshold.
+func (swc *SlidingWindowCounter) Reserve(key string) time.Duration {
+	return swc.take(key, 1)
+}
+
+// Wait blocks until the weighted count of key is under the threshold and counts the request,
+// or until ctx is done.
+func (swc *SlidingWindowCounter) Wait(ctx context.Context, key string) error {
+	return wait(ctx, func() time.Duration { return swc.take(key, 1) })
+}
+
+// GetState returns the counts of the current and previous windows of key, and when the current
+// one started.
+func (swc *SlidingWindowCounter) GetState(key string) map[string]any {
+	swc.mu.Lock()
+	defer swc.mu.Unlock()
+
+	now := swc.now()
+	var w slidingWindow
+	if stored := swc.windows.peek(key, now); stored != nil {
+		swc.advance(stored, now)
+		w = *stored
+	}
+	return map[string]any{
+		"currentCount":       w.currentWindowCount,
+		"previousCount":      w.previousWindowCount,
+		"duration":           swc.windowDuration,
+		"currentWindowStart": w.currentWindowStart,
+	}
+}
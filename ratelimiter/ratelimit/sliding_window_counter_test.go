@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+)
+
+func TestSlidingWindowCounterCreation(t *testing.T) {
+	swc := NewSlidingWindowCounter(10, 10*time.Second)
+	require.Equal(t, swc.windows.len(), 0)
+	require.Equal(t, swc.threshold, 10)
+	require.Equal(t, swc.windowDuration, 10*time.Second)
+}
+
+func TestAllowSwc(t *testing.T) {
+	swc := NewSlidingWindowCounter(10, 1*time.Second)
+	ip := "192.168.1.1"
+	for range 10 {
+		require.True(t, swc.Allow(ip))
+	}
+	require.False(t, swc.Allow(ip))
+	time.Sleep(1 * time.Second)
+	require.True(t, swc.Allow(ip))
+	require.False(t, swc.Allow(ip))
+}
+
+func TestReserveSwc(t *testing.T) {
+	clock := newFakeClock()
+	swc := NewSlidingWindowCounter(10, 10*time.Second, WithClock(clock.Now))
+	ip := "192.168.1.1"
+	require.True(t, swc.AllowN(ip, 10))
+
+	// Nothing fits before the previous window weighs less than the threshold
+	clock.Advance(5 * time.Second)
+	require.Equal(t, 5*time.Second+1, swc.Reserve(ip))
+
+	// Two seconds into the next window the previous one weighs 8
+	clock.Advance(7 * time.Second)
+	require.True(t, swc.AllowN(ip, 2))
+	require.False(t, swc.AllowN(ip, 3))
+	require.Equal(t, 2*time.Second+1, swc.take(ip, 3))
+
+	clock.Advance(2*time.Second + 1)
+	require.True(t, swc.AllowN(ip, 3))
+	require.Equal(t, 5, swc.GetState(ip)["currentCount"])
+}
+
+func TestKeysSwc(t *testing.T) {
+	clock := newFakeClock()
+	swc := NewSlidingWindowCounter(4, 10*time.Second, WithClock(clock.Now))
+
+	// Key A exhausting its quota does not affect key B
+	require.True(t, swc.AllowN("A", 4))
+	require.False(t, swc.Allow("A"))
+	for range 4 {
+		require.True(t, swc.Allow("B"))
+	}
+	require.False(t, swc.Allow("B"))
+
+	// Every key has its own windows, starting with its first request
+	clock.Advance(5 * time.Second)
+	require.True(t, swc.AllowN("C", 4))
+	clock.Advance(10 * time.Second)
+	require.True(t, swc.AllowN("A", 2))
+	require.False(t, swc.Allow("C"))
+	state := swc.GetState("C")
+	require.Equal(t, 0, state["currentCount"])
+	require.Equal(t, 4, state["previousCount"])
+}
+
+func TestEvictSwc(t *testing.T) {
+	clock := newFakeClock()
+	swc := NewSlidingWindowCounter(2, 10*time.Second, WithClock(clock.Now), WithMaxKeys(2))
+	require.True(t, swc.AllowN("A", 2))
+
+	// The previous window of a key still counts after one window
+	clock.Advance(10 * time.Second)
+	require.True(t, swc.Allow("B"))
+	require.Equal(t, 2, swc.windows.len())
+
+	// Past the bound the least recently used key starts over
+	require.True(t, swc.Allow("C"))
+	require.Equal(t, 2, swc.windows.len())
+	require.Equal(t, 0, swc.GetState("A")["previousCount"])
+
+	// Keys are dropped two windows after their last request
+	clock.Advance(20 * time.Second)
+	require.Equal(t, 0, swc.GetState("B")["currentCount"])
+	require.Equal(t, 0, swc.windows.len())
+}
+
+func TestGetStateSwc(t *testing.T) {
+	swc := NewSlidingWindowCounter(5, 2*time.Second) // threshold=5, window=2s
+	ip := "10.0.0.2"
+
+	require.True(t, swc.Allow(ip))
+	require.True(t, swc.Allow(ip))
+
+	state := swc.GetState(ip)
+	require.Equal(t, 2, state["currentCount"], "currentCount should be 2 after 2 requests")
+	require.Equal(t, 0, state["previousCount"], "previousCount should still be 0")
+}
@@ -0,0 +1,103 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// SlidingWindowLog keeps the time of every request of a key, and allows threshold requests in
+// any span of retention. Keys without requests for retention have nothing left in their log and
+// are dropped.
+type SlidingWindowLog struct {
+	threshold int
+	retention time.Duration
+	logs      *keyStore[[]time.Time]
+	mu        sync.Mutex
+	now       func() time.Time
+}
+
+// NewSlidingWindowLog returns a SlidingWindowLog that allows threshold requests per key in any
+// span of retention.
+func NewSlidingWindowLog(threshold int, retention time.Duration, opts ...Option) *SlidingWindowLog {
+	c := newConfig(opts)
+	return &SlidingWindowLog{
+		threshold: threshold,
+		retention: retention,
+		logs:      newKeyStore[[]time.Time](retention, c.maxKeys),
+		now:       c.now,
+	}
+}
+
+// expire drops the requests in times older than retention.
+func (sl *SlidingWindowLog) expire(times *[]time.Time, now time.Time) {
+	expired := 0
+	for expired < len(*times) && now.Sub((*times)[expired]) >= sl.retention {
+		expired++
+	}
+	*times = (*times)[expired:]
+}
+
+// take logs n requests of key and returns zero, or returns how long until enough requests
+// expire without logging them.
+func (sl *SlidingWindowLog) take(key string, n int) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	if n > sl.threshold {
+		return never
+	}
+
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	now := sl.now()
+	times := sl.logs.get(key, now)
+	sl.expire(times, now)
+	if excess := len(*times) + n - sl.threshold; excess > 0 {
+		return (*times)[excess-1].Add(sl.retention).Sub(now)
+	}
+	for range n {
+		*times = append(*times, now)
+	}
+	return 0
+}
+
+// Allow reports whether key is under the threshold, and logs the request when it is.
+func (sl *SlidingWindowLog) Allow(key string) bool {
+	return sl.take(key, 1) == 0
+}
+
+// AllowN reports whether key has room for n requests, and logs them when it does.
+func (sl *SlidingWindowLog) AllowN(key string, n int) bool {
+	return sl.take(key, n) == 0
+}
+
+// Reserve logs a request of key and returns zero, or returns how long until the oldest request
+// expires.
+func (sl *SlidingWindowLog) Reserve(key string) time.Duration {
+	return sl.take(key, 1)
+}
+
+// Wait blocks until key is under the threshold and logs the request, or until ctx is done.
+func (sl *SlidingWindowLog) Wait(ctx context.Context, key string) error {
+	return wait(ctx, func() time.Duration { return sl.take(key, 1) })
+}
+
+// GetState returns the requests of key in the log, the threshold and the retention.
+func (sl *SlidingWindowLog) GetState(key string) map[string]any {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+
+	now := sl.now()
+	count := 0
+	if times := sl.logs.peek(key, now); times != nil {
+		sl.expire(times, now)
+		count = len(*times)
+	}
+	return map[string]any{
+		"count":     count,
+		"threshold": sl.threshold,
+		"reset":     sl.retention,
+	}
+}
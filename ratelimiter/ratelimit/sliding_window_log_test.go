@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+)
+
+func TestSlidingWindowLogCreation(t *testing.T) {
+	slw := NewSlidingWindowLog(10, 10*time.Second)
+	require.Equal(t, slw.retention, 10*time.Second)
+	require.Equal(t, slw.threshold, 10)
+}
+
+func TestAllowSwl(t *testing.T) {
+	slw := NewSlidingWindowLog(5, 10*time.Second)
+	ip := "192.168.1.1"
+
+	for range 5 {
+		require.True(t, slw.Allow(ip))
+	}
+	require.False(t, slw.Allow(ip))
+}
+
+func TestExpireSwl(t *testing.T) {
+	clock := newFakeClock()
+	slw := NewSlidingWindowLog(3, 10*time.Second, WithClock(clock.Now))
+	ip := "192.168.1.1"
+
+	require.True(t, slw.AllowN(ip, 2))
+	clock.Advance(4 * time.Second)
+	require.True(t, slw.Allow(ip))
+	require.Equal(t, 6*time.Second, slw.Reserve(ip))
+	require.Equal(t, 10*time.Second, slw.take(ip, 3))
+
+	// The first two requests expire together, the last one later
+	clock.Advance(6 * time.Second)
+	require.True(t, slw.AllowN(ip, 2))
+	require.Equal(t, 4*time.Second, slw.Reserve(ip))
+	require.Equal(t, 3, slw.GetState(ip)["count"])
+
+	// The log of a key is dropped once all its requests expire
+	clock.Advance(10 * time.Second)
+	require.Equal(t, 0, slw.GetState(ip)["count"])
+	require.Equal(t, 0, slw.logs.len())
+}
+
+func TestEvictSwl(t *testing.T) {
+	clock := newFakeClock()
+	slw := NewSlidingWindowLog(2, 10*time.Second, WithClock(clock.Now), WithMaxKeys(2))
+	require.True(t, slw.AllowN("A", 2))
+	require.True(t, slw.AllowN("B", 2))
+
+	// Past the bound the least recently used key starts over
+	require.True(t, slw.Allow("C"))
+	require.Equal(t, 2, slw.logs.len())
+	require.Equal(t, 0, slw.GetState("A")["count"])
+	require.Equal(t, 2, slw.GetState("B")["count"])
+
+	// Keys are dropped once every request in their log expired
+	clock.Advance(10 * time.Second)
+	require.True(t, slw.Allow("D"))
+	require.Equal(t, 1, slw.logs.len())
+}
+
+func TestGetStateSwl(t *testing.T) {
+	slw := NewSlidingWindowLog(5, 10*time.Second)
+	ip := "192.168.1.1"
+
+	for range 5 {
+		require.True(t, slw.Allow(ip))
+	}
+
+	state := slw.GetState(ip)
+	require.Equal(t, state["count"], 5)
+	require.Equal(t, state["threshold"], slw.threshold)
+	require.Equal(t, state["reset"], slw.retention)
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket gives every key a bucket of tokens that refills at a steady rate. A request takes
+// a token, so bursts up to the size of the bucket are allowed. Buckets refill on the next
+// request of their key, and buckets idle for the idle timeout are dropped on the next request of
+// any key, so there is no background goroutine.
+type TokenBucket struct {
+	buckets *keyStore[bucket]
+	mu      sync.Mutex
+	// The maximum number of tokens in the bucket
+	maxTokens int
+	// The rate at which tokens are added to the bucket
+	rate time.Duration
+	now  func() time.Time
+}
+
+// NewTokenBucket returns a TokenBucket with buckets of maxTokens tokens that gain one token
+// every rate.
+func NewTokenBucket(maxTokens int, rate time.Duration, opts ...Option) *TokenBucket {
+	c := newConfig(opts)
+	return &TokenBucket{
+		buckets:   newKeyStore[bucket](c.idleTimeout, c.maxKeys),
+		maxTokens: maxTokens,
+		rate:      rate,
+		now:       c.now,
+	}
+}
+
+type bucket struct {
+	// The number of tokens in the bucket
+	tokens int
+	// The time the last token was added, or the bucket was last seen full
+	refilled time.Time
+	lastUsed time.Time
+}
+
+// refill adds the tokens the bucket gained since it was last refilled. A new bucket starts full.
+// The caller holds tb.mu.
+func (tb *TokenBucket) refill(b *bucket, now time.Time) {
+	if b.refilled.IsZero() || b.tokens >= tb.maxTokens {
+		b.tokens = tb.maxTokens
+		b.refilled = now
+		return
+	}
+
+	gained := now.Sub(b.refilled) / tb.rate
+	if gained <= 0 {
+		return
+	}
+	if gained >= time.Duration(tb.maxTokens-b.tokens) {
+		b.tokens = tb.maxTokens
+		b.refilled = now
+		return
+	}
+	b.tokens += int(gained)
+	b.refilled = b.refilled.Add(gained * tb.rate)
+}
+
+// take takes n tokens from the bucket of key and returns zero, or returns how long until the
+// bucket has n tokens without taking any.
+func (tb *TokenBucket) take(key string, n int) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	if n > tb.maxTokens {
+		return never
+	}
+
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := tb.now()
+	b := tb.buckets.get(key, now)
+	tb.refill(b, now)
+	if b.tokens < n {
+		return time.Duration(n-b.tokens)*tb.rate - now.Sub(b.refilled)
+	}
+	b.tokens -= n
+	b.lastUsed = now
+	return 0
+}
+
+// Allow reports whether key has a token, and takes it when it does.
+func (tb *TokenBucket) Allow(key string) bool {
+	return tb.take(key, 1) == 0
+}
+
+// AllowN reports whether key has n tokens, and takes them when it does.
+func (tb *TokenBucket) AllowN(key string, n int) bool {
+	return tb.take(key, n) == 0
+}
+
+// Reserve takes a token of key and returns zero, or returns how long until key has a token.
+func (tb *TokenBucket) Reserve(key string) time.Duration {
+	return tb.take(key, 1)
+}
+
+// Wait blocks until key has a token and takes it, or until ctx is done.
+func (tb *TokenBucket) Wait(ctx context.Context, key string) error {
+	return wait(ctx, func() time.Duration { return tb.take(key, 1) })
+}
+
+// GetState returns the tokens left for key and when it last took one. It does not create a
+// bucket for a key without one, whose bucket would be full.
+func (tb *TokenBucket) GetState(key string) map[string]any {
+	tb.mu.Lock()
+	defer tb.mu.Unlock()
+
+	now := tb.now()
+	b := bucket{tokens: tb.maxTokens}
+	if stored := tb.buckets.peek(key, now); stored != nil {
+		tb.refill(stored, now)
+		b = *stored
+	}
+	return map[string]any{
+		"tokens":   b.tokens,
+		"lastUsed": b.lastUsed,
+	}
+}
@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+)
+
+func TestBucketCreation(t *testing.T) {
+	tb := NewTokenBucket(10, 1*time.Second)
+	require.Equal(t, 0, tb.buckets.len())
+	require.True(t, tb.Allow("192.168.1.1"))
+	require.Equal(t, 1, tb.buckets.len())
+	require.Equal(t, 9, tb.GetState("192.168.1.1")["tokens"])
+}
+
+func TestTokenRefill(t *testing.T) {
+	tb := NewTokenBucket(5, 100*time.Millisecond)
+	ip := "192.168.1.1"
+	for range 5 {
+		require.True(t, tb.Allow(ip))
+	}
+	require.False(t, tb.Allow(ip))
+
+	time.Sleep(1 * time.Second)
+	if tokens := tb.GetState(ip)["tokens"]; tokens != tb.maxTokens {
+		t.Errorf("tokens should be refilled, got %v", tokens)
+	}
+}
+
+func TestReserveTb(t *testing.T) {
+	clock := newFakeClock()
+	tb := NewTokenBucket(3, 1*time.Second, WithClock(clock.Now))
+	ip := "192.168.1.1"
+	require.True(t, tb.AllowN(ip, 3))
+	require.Equal(t, 1*time.Second, tb.Reserve(ip))
+
+	// A token is added every second, counted from when the last one was
+	clock.Advance(1500 * time.Millisecond)
+	require.Zero(t, tb.Reserve(ip))
+	require.Equal(t, 500*time.Millisecond, tb.Reserve(ip))
+	require.Equal(t, 2500*time.Millisecond, tb.take(ip, 3))
+	require.False(t, tb.AllowN(ip, 4))
+
+	// The bucket does not fill over its size
+	clock.Advance(1 * time.Hour)
+	require.Equal(t, 3, tb.GetState(ip)["tokens"])
+}
+
+func TestAllowTb(t *testing.T) {
+	tb := NewTokenBucket(3, 1*time.Second)
+	ip := "192.168.1.1"
+	for range 3 {
+		require.True(t, tb.Allow(ip))
+	}
+	require.False(t, tb.Allow(ip))
+}
+
+func TestEvictTb(t *testing.T) {
+	clock := newFakeClock()
+	tb := NewTokenBucket(3, 100*time.Millisecond, WithClock(clock.Now), WithIdleTimeout(1*time.Second), WithMaxKeys(2))
+	require.True(t, tb.AllowN("A", 3))
+	require.True(t, tb.Allow("B"))
+
+	// Past the bound the least recently used bucket is dropped, and is full again
+	require.True(t, tb.Allow("C"))
+	require.Equal(t, 2, tb.buckets.len())
+	require.Equal(t, 3, tb.GetState("A")["tokens"])
+
+	// Idle buckets are dropped on the next request
+	clock.Advance(1 * time.Second)
+	require.True(t, tb.Allow("D"))
+	require.Equal(t, 1, tb.buckets.len())
+}
+
+func TestNoIdleTimeoutTb(t *testing.T) {
+	clock := newFakeClock()
+	tb := NewTokenBucket(3, 1*time.Second, WithClock(clock.Now), WithIdleTimeout(0))
+	tb.Allow("A")
+	clock.Advance(1 * time.Hour)
+	tb.Allow("B")
+	require.Equal(t, 2, tb.buckets.len())
+}
+
+func TestGetStateTb(t *testing.T) {
+	tb := NewTokenBucket(3, 1*time.Millisecond, WithIdleTimeout(10*time.Second))
+	ip := "192.168.1.1"
+	mp := tb.GetState(ip)
+	require.Equal(t, 3, mp["tokens"])
+
+	// Reading the state of a key does not create its bucket
+	require.Equal(t, 0, tb.buckets.len())
+}